@@ -0,0 +1,141 @@
+package godinez
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/golangcollege/sessions"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// App holds the dependencies used by Render and the middleware package, so
+// it can be passed wherever they expect an application.
+type App struct {
+	infoLog       *log.Logger
+	errorLog      *log.Logger
+	session       *sessions.Session
+	templateCache map[string]*template.Template
+	authenticate  func(*http.Request) bool
+	redirectTo    string
+
+	readTimeout     time.Duration
+	writeTimeout    time.Duration
+	idleTimeout     time.Duration
+	shutdownTimeout time.Duration
+	tlsConfig       *tls.Config
+
+	mu            sync.Mutex
+	shutdownHooks []func(context.Context) error
+	checks        []namedChecker
+	checkTimeout  time.Duration
+	checkErrors   bool
+	shuttingDown  atomic.Bool
+	drainDelay    time.Duration
+}
+
+// Option configures an *App.
+type Option func(*App)
+
+// NewApp initializes an *App logging to stdout and stderr, authenticating
+// requests with IsAuthenticated and redirecting to "/user/login". Its
+// servers time out reads after 5s, writes after 10s, idle connections
+// after 1m and are given 30s to shut down.
+func NewApp(opts ...Option) *App {
+	app := &App{
+		infoLog:       log.New(os.Stdout, "INFO\t", log.Ldate|log.Ltime),
+		errorLog:      log.New(os.Stderr, "ERROR\t", log.Ldate|log.Ltime|log.Lshortfile),
+		templateCache: map[string]*template.Template{},
+		authenticate:  IsAuthenticated,
+		redirectTo:    "/user/login",
+
+		readTimeout:     5 * time.Second,
+		writeTimeout:    10 * time.Second,
+		idleTimeout:     time.Minute,
+		shutdownTimeout: 30 * time.Second,
+		checkTimeout:    2 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(app)
+	}
+	return app
+}
+
+func WithInfoLogger(l *log.Logger) Option {
+	return func(app *App) {
+		app.infoLog = l
+	}
+}
+
+func WithErrorLogger(l *log.Logger) Option {
+	return func(app *App) {
+		app.errorLog = l
+	}
+}
+
+func WithSession(s *sessions.Session) Option {
+	return func(app *App) {
+		app.session = s
+	}
+}
+
+// WithTemplateCache sets the templates used by Render, usually created
+// with NewTemplateCache.
+func WithTemplateCache(cache map[string]*template.Template) Option {
+	return func(app *App) {
+		app.templateCache = cache
+	}
+}
+
+// WithAuthenticator replaces IsAuthenticated to resolve whether a request
+// is authenticated.
+func WithAuthenticator(fn func(*http.Request) bool) Option {
+	return func(app *App) {
+		app.authenticate = fn
+	}
+}
+
+// WithRedirectTo sets where RequireAuthentication redirects to.
+func WithRedirectTo(url string) Option {
+	return func(app *App) {
+		app.redirectTo = url
+	}
+}
+
+func (app *App) GetInfoLogger() *log.Logger {
+	return app.infoLog
+}
+
+func (app *App) GetErrorLogger() *log.Logger {
+	return app.errorLog
+}
+
+func (app *App) GetSession() *sessions.Session {
+	return app.session
+}
+
+func (app *App) GetTemplateCache(name string) (*template.Template, error) {
+	ts, ok := app.templateCache[name]
+	if !ok {
+		return nil, fmt.Errorf("The template %s does not exist", name)
+	}
+	return ts, nil
+}
+
+func (app *App) IsAuthenticated(r *http.Request) bool {
+	return app.authenticate(r)
+}
+
+func (app *App) GetRedirectTo() string {
+	return app.redirectTo
+}
+
+// Render renders the template name with data, see Render.
+func (app *App) Render(w http.ResponseWriter, r *http.Request, name string, data interface{}) {
+	Render(app, data, w, r, name)
+}
@@ -0,0 +1,102 @@
+package godinez
+
+import (
+	"bytes"
+	"context"
+	"github.com/tomascaslo/godinez/tracing"
+	"html/template"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewApp(t *testing.T) {
+	logBuf := new(bytes.Buffer)
+	errorLog := log.New(logBuf, "", 0)
+	templ := template.Must(template.New("home.page.tmpl").Parse(`{{.Data}}`))
+	app := NewApp(
+		WithErrorLogger(errorLog),
+		WithTemplateCache(map[string]*template.Template{"home.page.tmpl": templ}),
+		WithRedirectTo("/login"),
+	)
+
+	if app.GetErrorLogger() != errorLog {
+		t.Errorf("Expected %v got %v", errorLog, app.GetErrorLogger())
+	}
+	if app.GetInfoLogger() == nil {
+		t.Errorf("Expected a default info logger")
+	}
+	if app.GetRedirectTo() != "/login" {
+		t.Errorf("Expected %q got %q", "/login", app.GetRedirectTo())
+	}
+
+	t.Run("Render", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		app.Render(rr, httptest.NewRequest("GET", "/", nil), "home.page.tmpl", NewPage("Hello"))
+
+		if rr.Body.String() != "Hello" {
+			t.Errorf("Expected %q got %q", "Hello", rr.Body.String())
+		}
+	})
+
+	t.Run("Missing template", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		app.Render(rr, httptest.NewRequest("GET", "/", nil), "missing.page.tmpl", nil)
+
+		if rr.Code != http.StatusInternalServerError {
+			t.Errorf("Expected %d got %d", http.StatusInternalServerError, rr.Code)
+		}
+		if logBuf.Len() == 0 {
+			t.Errorf("Expected the error to be logged")
+		}
+	})
+}
+
+func TestAppIsAuthenticated(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	authenticated := r.WithContext(context.WithValue(r.Context(), ContextKeyIsAuthenticated, true))
+	tests := []struct {
+		name     string
+		app      *App
+		r        *http.Request
+		expected bool
+	}{
+		{"Default without context", NewApp(), r, false},
+		{"Default with context", NewApp(), authenticated, true},
+		{"Custom authenticator", NewApp(WithAuthenticator(func(*http.Request) bool { return true })), r, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.app.IsAuthenticated(tt.r); actual != tt.expected {
+				t.Errorf("Expected %t got %t", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRenderTrace(t *testing.T) {
+	exporter := &tracing.InMemoryExporter{}
+	tracing.SetExporter(exporter)
+	defer tracing.SetExporter(nil)
+	templ := template.Must(template.New("home.page.tmpl").Parse(`{{.Data}}`))
+	app := NewApp(WithTemplateCache(map[string]*template.Template{"home.page.tmpl": templ}))
+	ctx, parent := tracing.Start(context.Background(), "request")
+	r := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+
+	app.Render(httptest.NewRecorder(), r, "home.page.tmpl", NewPage("Hello"))
+
+	spans := exporter.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected %d spans got %d", 1, len(spans))
+	}
+	if spans[0].Parent != parent.SpanContext.SpanID {
+		t.Errorf("Expected the render span to be a child of the request span")
+	}
+	if spans[0].Attributes["template"] != "home.page.tmpl" || spans[0].Attributes["bytes"] != 5 {
+		t.Errorf("Unexpected attributes %v", spans[0].Attributes)
+	}
+}
@@ -0,0 +1,98 @@
+package godinez
+
+import (
+	"github.com/tomascaslo/godinez/i18n"
+	"net/http"
+	"time"
+)
+
+var ContextKeyLocation = contextKey("location")
+
+// now is replaced in tests.
+var now = time.Now
+
+// LocationFromRequest returns the time zone set by middleware.Timezone,
+// or UTC if there is none.
+func LocationFromRequest(r *http.Request) *time.Location {
+	loc, ok := r.Context().Value(ContextKeyLocation).(*time.Location)
+	if !ok || loc == nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// HumanDateIn formats t with HumanDateLayout in the time zone loc.
+// A nil loc is treated as UTC.
+func HumanDateIn(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format(HumanDateLayout)
+}
+
+// HumanDateLocaleIn is HumanDateLocale in the time zone loc.
+func HumanDateLocaleIn(t time.Time, loc *time.Location, locale string) string {
+	if t.IsZero() {
+		return ""
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	layout := i18n.Default.Translate(locale, i18n.HumanDate)
+	return i18n.Default.FormatTime(locale, t.In(loc), layout)
+}
+
+// TimeAgo describes t relative to now in English, e.g. "3 hours ago"
+// or "in 2 days".
+func TimeAgo(t time.Time) string {
+	return RelativeTime(t, "en")
+}
+
+var relativeUnits = []struct {
+	d   time.Duration
+	key string
+}{
+	{365 * 24 * time.Hour, i18n.Year},
+	{30 * 24 * time.Hour, i18n.Month},
+	{24 * time.Hour, i18n.Day},
+	{time.Hour, i18n.Hour},
+	{time.Minute, i18n.Minute},
+	{time.Second, i18n.Second},
+}
+
+// RelativeTime describes t relative to now in locale using the largest
+// whole unit, e.g. "hace 3 horas" or "en 2 días" for "es".
+// Differences under a minute are described as "just now".
+func RelativeTime(t time.Time, locale string) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := now().Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	if d < time.Minute {
+		return i18n.Default.Translate(locale, i18n.JustNow)
+	}
+
+	for _, u := range relativeUnits {
+		if d < u.d {
+			continue
+		}
+		n := int(d / u.d)
+		key := u.key
+		if n != 1 {
+			key += ".other"
+		}
+		amount := i18n.Default.Translate(locale, key, n)
+		if future {
+			return i18n.Default.Translate(locale, i18n.In, amount)
+		}
+		return i18n.Default.Translate(locale, i18n.Ago, amount)
+	}
+	return ""
+}
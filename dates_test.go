@@ -0,0 +1,79 @@
+package godinez
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHumanDateIn(t *testing.T) {
+	mexico, err := time.LoadLocation("America/Mexico_City")
+	if err != nil {
+		t.Skip(err)
+	}
+	date := time.Date(2019, time.January, 2, 15, 4, 0, 0, time.UTC)
+
+	actual := HumanDateIn(date, mexico)
+	expected := "02 Jan 2019 at 09:04"
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+
+	actual = HumanDateLocaleIn(date, mexico, "es")
+	expected = "02 ene 2019 a las 09:04"
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+
+	actual = HumanDateIn(date, nil)
+	expected = "02 Jan 2019 at 15:04"
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+}
+
+func TestLocationFromRequest(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	if actual := LocationFromRequest(req); actual != time.UTC {
+		t.Errorf("Expected %v got %v", time.UTC, actual)
+	}
+
+	loc := time.FixedZone("CST", -6*60*60)
+	req = req.WithContext(context.WithValue(req.Context(), ContextKeyLocation, loc))
+	if actual := LocationFromRequest(req); actual != loc {
+		t.Errorf("Expected %v got %v", loc, actual)
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	current := time.Date(2019, time.May, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	tests := []struct {
+		t        time.Time
+		locale   string
+		expected string
+	}{
+		{current.Add(-30 * time.Second), "en", "just now"},
+		{current.Add(-time.Minute), "en", "1 minute ago"},
+		{current.Add(-3 * time.Hour), "en", "3 hours ago"},
+		{current.Add(49 * time.Hour), "en", "in 2 days"},
+		{current.Add(-400 * 24 * time.Hour), "en", "1 year ago"},
+		{current.Add(-3 * time.Hour), "es", "hace 3 horas"},
+		{current.Add(24 * time.Hour), "es", "en 1 día"},
+		{time.Time{}, "en", ""},
+	}
+
+	for _, tt := range tests {
+		actual := RelativeTime(tt.t, tt.locale)
+		if actual != tt.expected {
+			t.Errorf("Expected %q got %q", tt.expected, actual)
+		}
+	}
+
+	if actual := TimeAgo(current.Add(-2 * time.Minute)); actual != "2 minutes ago" {
+		t.Errorf("Expected %q got %q", "2 minutes ago", actual)
+	}
+}
@@ -0,0 +1,197 @@
+package godinez
+
+import (
+	"fmt"
+	"github.com/tomascaslo/godinez/forms"
+	"html/template"
+	"strings"
+)
+
+// FieldErrorClass is added to fields with errors and to their messages.
+var FieldErrorClass = "error"
+
+// CSRFFieldName is the form field nosurf reads the CSRF token from.
+const CSRFFieldName = "csrf_token"
+
+// fieldAttrs writes the attributes shared by every field: id, name, the
+// error class and the ARIA attributes linking the field to its messages.
+func fieldAttrs(b *strings.Builder, form *forms.Form, name string, extra []string) {
+	fmt.Fprintf(b, ` id="%s" name="%s"`, escape(name), escape(name))
+	errorAttrs(b, form, name, extra)
+	extraAttrs(b, extra)
+}
+
+// errorAttrs writes the class of the field, adding FieldErrorClass to the
+// class given in extra when name has errors, and the ARIA attributes.
+func errorAttrs(b *strings.Builder, form *forms.Form, name string, extra []string) {
+	var classes []string
+	if class := attr(extra, "class"); class != "" {
+		classes = append(classes, class)
+	}
+	invalid := hasErrors(form, name)
+	if invalid {
+		classes = append(classes, FieldErrorClass)
+	}
+	if len(classes) > 0 {
+		fmt.Fprintf(b, ` class="%s"`, escape(strings.Join(classes, " ")))
+	}
+	if invalid {
+		fmt.Fprintf(b, ` aria-invalid="true" aria-describedby="%s-error"`, escape(name))
+	}
+}
+
+// attr returns the value of key in the name/value pairs of extra.
+func attr(extra []string, key string) string {
+	for i := 0; i+1 < len(extra); i += 2 {
+		if strings.EqualFold(extra[i], key) {
+			return extra[i+1]
+		}
+	}
+	return ""
+}
+
+// extraAttrs writes name/value pairs, a pair with an empty value is
+// written as a boolean attribute e.g. "required" "". The class is written
+// by errorAttrs.
+func extraAttrs(b *strings.Builder, extra []string) {
+	for i := 0; i+1 < len(extra); i += 2 {
+		if strings.EqualFold(extra[i], "class") {
+			continue
+		}
+		if extra[i+1] == "" {
+			fmt.Fprintf(b, ` %s`, escape(extra[i]))
+			continue
+		}
+		fmt.Fprintf(b, ` %s="%s"`, escape(extra[i]), escape(extra[i+1]))
+	}
+}
+
+func escape(s string) string {
+	return template.HTMLEscapeString(s)
+}
+
+func hasErrors(form *forms.Form, name string) bool {
+	return form != nil && len(form.Errors[name]) > 0
+}
+
+func formValue(form *forms.Form, name string) string {
+	if form == nil {
+		return ""
+	}
+	return form.Get(name)
+}
+
+// FieldErrors renders the messages of name, identified so that the
+// field's aria-describedby points to them.
+func FieldErrors(form *forms.Form, name string) template.HTML {
+	if !hasErrors(form, name) {
+		return ""
+	}
+	b := new(strings.Builder)
+	fmt.Fprintf(b, `<div id="%s-error" class="%s" role="alert">`, escape(name), escape(FieldErrorClass))
+	for i, msg := range form.Errors[name] {
+		if i > 0 {
+			b.WriteString("<br>")
+		}
+		b.WriteString(escape(msg))
+	}
+	b.WriteString("</div>")
+	return template.HTML(b.String())
+}
+
+// Input renders an input of type typ with the submitted value and its
+// errors. Password inputs are never repopulated. Use it in templates as
+// {{input .Form "email" "email" "placeholder" "john@example.com"}}.
+func Input(form *forms.Form, name, typ string, attrs ...string) template.HTML {
+	b := new(strings.Builder)
+	fmt.Fprintf(b, `<input type="%s"`, escape(typ))
+	fieldAttrs(b, form, name, attrs)
+	if typ != "password" {
+		fmt.Fprintf(b, ` value="%s"`, escape(formValue(form, name)))
+	}
+	b.WriteString(">")
+	b.WriteString(string(FieldErrors(form, name)))
+	return template.HTML(b.String())
+}
+
+// Textarea renders a textarea with the submitted value and its errors.
+func Textarea(form *forms.Form, name string, attrs ...string) template.HTML {
+	b := new(strings.Builder)
+	b.WriteString("<textarea")
+	fieldAttrs(b, form, name, attrs)
+	fmt.Fprintf(b, ">%s</textarea>", escape(formValue(form, name)))
+	b.WriteString(string(FieldErrors(form, name)))
+	return template.HTML(b.String())
+}
+
+// Options groups the value/label pairs of a select so that they can be
+// followed by attributes: {{select .Form "plan" (options "free" "Free" "pro" "Pro") "required" ""}}.
+func Options(pairs ...string) []string {
+	return pairs
+}
+
+// Select renders a select with the submitted options selected, several of
+// them when it has the multiple attribute. options are value/label pairs,
+// see Options.
+func Select(form *forms.Form, name string, options []string, attrs ...string) template.HTML {
+	b := new(strings.Builder)
+	b.WriteString("<select")
+	fieldAttrs(b, form, name, attrs)
+	b.WriteString(">")
+	var values []string
+	if form != nil {
+		values = form.Values[name]
+	}
+	for i := 0; i+1 < len(options); i += 2 {
+		selected := ""
+		for _, value := range values {
+			if options[i] == value {
+				selected = " selected"
+				break
+			}
+		}
+		fmt.Fprintf(b, `<option value="%s"%s>%s</option>`, escape(options[i]), selected, escape(options[i+1]))
+	}
+	b.WriteString("</select>")
+	b.WriteString(string(FieldErrors(form, name)))
+	return template.HTML(b.String())
+}
+
+// Checkbox renders a checkbox checked when value was submitted for name,
+// which supports several checkboxes sharing a name.
+func Checkbox(form *forms.Form, name, value string, attrs ...string) template.HTML {
+	checked := false
+	if form != nil {
+		for _, v := range form.Values[name] {
+			if v == value {
+				checked = true
+			}
+		}
+	}
+	return choice("checkbox", form, name, value, checked, attrs)
+}
+
+// Radio renders a radio button checked when value was submitted for name.
+func Radio(form *forms.Form, name, value string, attrs ...string) template.HTML {
+	return choice("radio", form, name, value, formValue(form, name) == value, attrs)
+}
+
+// choice renders checkboxes and radios. Their ids include the value since
+// several of them share a name, and errors are rendered with FieldErrors.
+func choice(typ string, form *forms.Form, name, value string, checked bool, attrs []string) template.HTML {
+	b := new(strings.Builder)
+	fmt.Fprintf(b, `<input type="%s" id="%s-%s" name="%s" value="%s"`, typ, escape(name), escape(value), escape(name), escape(value))
+	errorAttrs(b, form, name, attrs)
+	if checked {
+		b.WriteString(" checked")
+	}
+	extraAttrs(b, attrs)
+	b.WriteString(">")
+	return template.HTML(b.String())
+}
+
+// CSRFField renders the hidden input holding the CSRF token checked by
+// middleware.NoSurf: {{csrfField .CSRFToken}}.
+func CSRFField(token string) template.HTML {
+	return template.HTML(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`, CSRFFieldName, escape(token)))
+}
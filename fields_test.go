@@ -0,0 +1,135 @@
+package godinez
+
+import (
+	"bytes"
+	"github.com/tomascaslo/godinez/forms"
+	"html/template"
+	"net/url"
+	"testing"
+)
+
+func newTestForm() *forms.Form {
+	values := url.Values{}
+	values.Set("title", `My "first" <post>`)
+	values.Set("password", "secret")
+	values.Set("plan", "pro")
+	values["tags"] = []string{"go", "web"}
+	values.Set("visibility", "public")
+	form := forms.New(values)
+	form.Errors.Add("title", "This field is too long (maximum is 10 characters)")
+	return form
+}
+
+func TestInput(t *testing.T) {
+	form := newTestForm()
+	tests := []struct {
+		name     string
+		actual   template.HTML
+		expected template.HTML
+	}{
+		{
+			"Input with errors",
+			Input(form, "title", "text", "required", ""),
+			`<input type="text" id="title" name="title" class="error" aria-invalid="true" aria-describedby="title-error" required value="My &#34;first&#34; &lt;post&gt;">` +
+				`<div id="title-error" class="error" role="alert">This field is too long (maximum is 10 characters)</div>`,
+		},
+		{
+			"Class merged with the error class",
+			Input(form, "title", "text", "class", "wide"),
+			`<input type="text" id="title" name="title" class="wide error" aria-invalid="true" aria-describedby="title-error" value="My &#34;first&#34; &lt;post&gt;">` +
+				`<div id="title-error" class="error" role="alert">This field is too long (maximum is 10 characters)</div>`,
+		},
+		{
+			"Password is not repopulated",
+			Input(form, "password", "password"),
+			`<input type="password" id="password" name="password">`,
+		},
+		{
+			"Nil form",
+			Input(nil, "email", "email", "placeholder", "john@example.com"),
+			`<input type="email" id="email" name="email" placeholder="john@example.com" value="">`,
+		},
+		{
+			"Textarea",
+			Textarea(form, "plan", "rows", "3"),
+			`<textarea id="plan" name="plan" rows="3">pro</textarea>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.actual != tt.expected {
+				t.Errorf("Expected %s got %s", tt.expected, tt.actual)
+			}
+		})
+	}
+}
+
+func TestChoiceFields(t *testing.T) {
+	form := newTestForm()
+	tests := []struct {
+		name     string
+		actual   template.HTML
+		expected template.HTML
+	}{
+		{
+			"Select",
+			Select(form, "plan", Options("free", "Free", "pro", "Pro")),
+			`<select id="plan" name="plan"><option value="free">Free</option><option value="pro" selected>Pro</option></select>`,
+		},
+		{
+			"Select with attributes",
+			Select(form, "tags", Options("go", "Go", "rust", "Rust", "web", "Web"), "multiple", "", "class", "wide"),
+			`<select id="tags" name="tags" class="wide" multiple><option value="go" selected>Go</option><option value="rust">Rust</option><option value="web" selected>Web</option></select>`,
+		},
+		{
+			"Checked checkbox",
+			Checkbox(form, "tags", "web"),
+			`<input type="checkbox" id="tags-web" name="tags" value="web" checked>`,
+		},
+		{
+			"Unchecked checkbox",
+			Checkbox(form, "tags", "rust"),
+			`<input type="checkbox" id="tags-rust" name="tags" value="rust">`,
+		},
+		{
+			"Radio",
+			Radio(form, "visibility", "public"),
+			`<input type="radio" id="visibility-public" name="visibility" value="public" checked>`,
+		},
+		{
+			"CSRF field",
+			CSRFField(`abc"123`),
+			`<input type="hidden" name="csrf_token" value="abc&#34;123">`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.actual != tt.expected {
+				t.Errorf("Expected %s got %s", tt.expected, tt.actual)
+			}
+		})
+	}
+}
+
+func TestFieldTemplateFuncs(t *testing.T) {
+	templ := template.Must(template.New("page").Funcs(functions).Parse(
+		`<form>{{csrfField .CSRFToken}}{{input .Form "plan" "text"}}{{fieldErrors .Form "plan"}}` +
+			`{{select .Form "plan" (options "free" "Free" "pro" "Pro") "required" ""}}</form>`))
+	data := struct {
+		CSRFToken string
+		Form      *forms.Form
+	}{"token", newTestForm()}
+	buf := new(bytes.Buffer)
+
+	if err := templ.Execute(buf, data); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<form><input type="hidden" name="csrf_token" value="token"><input type="text" id="plan" name="plan" value="pro">` +
+		`<select id="plan" name="plan" required><option value="free">Free</option><option value="pro" selected>Pro</option></select></form>`
+	if buf.String() != expected {
+		t.Errorf("Expected %s got %s", expected, buf.String())
+	}
+}
@@ -0,0 +1,154 @@
+package forms
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+)
+
+// ValidatorFunc validates value and may query a database or other
+// service, so it receives the request context. A non-nil error fails the
+// field, see AddError for how it becomes a message.
+type ValidatorFunc func(ctx context.Context, value string) error
+
+// ValidationError is an error whose text is meant for the user, a message
+// or message key, e.g. ValidationError("This username is already taken").
+// AddError shows it as it is, unlike other unregistered errors.
+type ValidationError string
+
+func (e ValidationError) Error() string {
+	return string(e)
+}
+
+// FieldCheck pairs a field with a ValidatorFunc for CheckConcurrently.
+type FieldCheck struct {
+	Field string
+	Fn    ValidatorFunc
+}
+
+type errorMessage struct {
+	err     error
+	message string
+}
+
+var (
+	registryMu    sync.RWMutex
+	errorMessages = []errorMessage{
+		{context.DeadlineExceeded, MsgValidationFailed},
+		{context.Canceled, MsgValidationFailed},
+	}
+)
+
+// RegisterRule adds a named rule usable in validate tags and Form.Validate.
+// fn receives the field value and the rule parameter, e.g. "3" for "tags=3",
+// and returns whether the value is valid and the message, or message key,
+// to add otherwise.
+// Registering an existing name replaces the rule.
+func RegisterRule(name string, fn func(value, param string) (bool, string)) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	rules[name] = func(f *Form, field, param string) error {
+		f.Check(field, func(value string) (bool, string) {
+			return fn(value, param)
+		})
+		return nil
+	}
+}
+
+// RegisterErrorMessage sets the message, or message key, AddError uses for
+// errors matching err, e.g. RegisterErrorMessage(ErrDuplicateEmail, "duplicate_email").
+func RegisterErrorMessage(err error, message string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for i, em := range errorMessages {
+		if em.err == err {
+			errorMessages[i].message = message
+			return
+		}
+	}
+	errorMessages = append(errorMessages, errorMessage{err, message})
+}
+
+// Check runs fn with the value of field and adds msg when it is not ok.
+// msg is translated if it is a message key.
+func (f *Form) Check(field string, fn func(value string) (ok bool, msg string)) {
+	if ok, msg := fn(f.Get(field)); !ok {
+		f.addMessage(field, msg)
+	}
+}
+
+// Validate runs the comma separated rules in tag on field, using the same
+// syntax as the validate struct tag, e.g. "required,max=100,email".
+func (f *Form) Validate(field, tag string) error {
+	return f.validate(field, tag)
+}
+
+// AddError adds err to field. Errors registered with RegisterErrorMessage
+// are replaced by their message and a ValidationError is added as it is.
+// Any other error, such as a failed database query, must not reach the
+// user: field gets MsgValidationFailed and err is kept for Err.
+func (f *Form) AddError(field string, err error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for _, em := range errorMessages {
+		if stderrors.Is(err, em.err) {
+			f.addMessage(field, em.message)
+			return
+		}
+	}
+	var ve ValidationError
+	if stderrors.As(err, &ve) {
+		f.addMessage(field, string(ve))
+		return
+	}
+	f.addMessage(field, MsgValidationFailed)
+	f.internalErrs = append(f.internalErrs, fmt.Errorf("forms: %s: %w", field, err))
+}
+
+// Err returns the errors AddError hid from the user, joined, so that they
+// can be logged. It is nil if there were none.
+func (f *Form) Err() error {
+	return stderrors.Join(f.internalErrs...)
+}
+
+// CheckContext runs fn with the value of field and adds its error, if any.
+func (f *Form) CheckContext(ctx context.Context, field string, fn ValidatorFunc) {
+	if err := fn(ctx, f.Get(field)); err != nil {
+		f.AddError(field, err)
+	}
+}
+
+// CheckConcurrently runs checks at the same time, which is useful when
+// several of them query a database, and waits for all of them to finish.
+// Errors are added in the order of checks.
+func (f *Form) CheckConcurrently(ctx context.Context, checks ...FieldCheck) {
+	errs := make([]error, len(checks))
+	panics := make([]interface{}, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c FieldCheck) {
+			defer wg.Done()
+			// Panics are re-raised below so they reach RecoverPanic
+			// instead of crashing the server from this goroutine.
+			defer func() {
+				panics[i] = recover()
+			}()
+			errs[i] = c.Fn(ctx, f.Get(c.Field))
+		}(i, c)
+	}
+	wg.Wait()
+
+	for i, p := range panics {
+		if p != nil {
+			panic(fmt.Sprintf("forms: validator for %s panicked: %v", checks[i].Field, p))
+		}
+	}
+
+	for i, err := range errs {
+		if err != nil {
+			f.AddError(checks[i].Field, err)
+		}
+	}
+}
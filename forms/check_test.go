@@ -0,0 +1,161 @@
+package forms
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	form := New(url.Values{"username": {"admin"}, "nickname": {"johnny"}})
+	notReserved := func(value string) (bool, string) {
+		return value != "admin", "This username is reserved"
+	}
+
+	form.Check("username", notReserved)
+	form.Check("nickname", notReserved)
+
+	actual := form.Errors.Get("username")
+	expected := "This username is reserved"
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+
+	actual = form.Errors.Get("nickname")
+	expected = ""
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	RegisterRule("maxwords", func(value, param string) (bool, string) {
+		n, _ := strconv.Atoi(param)
+		return len(strings.Fields(value)) <= n, fmt.Sprintf("This field has more than %d words", n)
+	})
+	defer delete(rules, "maxwords")
+
+	form := New(url.Values{"title": {"one two three"}})
+	if err := form.Validate("title", "required,maxwords=2"); err != nil {
+		t.Fatal(err)
+	}
+
+	actual := form.Errors.Get("title")
+	expected := "This field has more than 2 words"
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+
+	if err := form.Validate("title", "unknown"); err == nil {
+		t.Error("Expected error for unknown rule")
+	}
+}
+
+func TestAddError(t *testing.T) {
+	errTaken := stderrors.New("models: username taken")
+	RegisterErrorMessage(errTaken, "This username is already taken")
+
+	form := New(url.Values{})
+	form.AddError("username", fmt.Errorf("insert user: %w", errTaken))
+	form.AddError("email", ValidationError("This email is not allowed"))
+	form.AddError("name", fmt.Errorf("check name: %w", ValidationError(MsgInvalid)))
+	form.AddError("nickname", stderrors.New("dial tcp 10.0.0.3:5432: connection refused"))
+
+	tests := []struct {
+		field    string
+		expected string
+	}{
+		{"username", "This username is already taken"},
+		{"email", "This email is not allowed"},
+		{"name", "This field is invalid"},
+		{"nickname", "This field could not be validated, please try again"},
+	}
+	for _, tt := range tests {
+		if actual := form.Errors.Get(tt.field); actual != tt.expected {
+			t.Errorf("Expected %q got %q", tt.expected, actual)
+		}
+	}
+
+	expected := "forms: nickname: dial tcp 10.0.0.3:5432: connection refused"
+	if form.Err() == nil || form.Err().Error() != expected {
+		t.Errorf("Expected %q got %v", expected, form.Err())
+	}
+	if New(url.Values{}).Err() != nil {
+		t.Errorf("Expected no error")
+	}
+}
+
+func TestCheckContext(t *testing.T) {
+	errTaken := ValidationError("taken")
+	taken := map[string]bool{"john@test.com": true}
+	emailAvailable := func(ctx context.Context, value string) error {
+		if taken[value] {
+			return errTaken
+		}
+		return nil
+	}
+
+	form := New(url.Values{"email": {"john@test.com"}})
+	form.CheckContext(context.Background(), "email", emailAvailable)
+
+	actual := form.Errors.Get("email")
+	expected := "taken"
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+}
+
+func TestCheckConcurrently(t *testing.T) {
+	slow := func(err error) ValidatorFunc {
+		return func(ctx context.Context, value string) error {
+			select {
+			case <-time.After(10 * time.Millisecond):
+				return err
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	form := New(url.Values{"email": {"john@test.com"}, "username": {"john"}})
+	form.CheckConcurrently(context.Background(),
+		FieldCheck{"email", slow(ValidationError("Email taken"))},
+		FieldCheck{"username", slow(nil)},
+	)
+
+	if actual := form.Errors.Get("email"); actual != "Email taken" {
+		t.Errorf("Expected %q got %q", "Email taken", actual)
+	}
+	if actual := form.Errors.Get("username"); actual != "" {
+		t.Errorf("Expected %q got %q", "", actual)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	form = New(url.Values{})
+	form.CheckConcurrently(ctx, FieldCheck{"email", slow(nil)})
+
+	actual := form.Errors.Get("email")
+	expected := "This field could not be validated, please try again"
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+}
+
+func TestCheckConcurrentlyPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic to be re-raised")
+		}
+	}()
+
+	form := New(url.Values{})
+	form.CheckConcurrently(context.Background(), FieldCheck{"email", func(context.Context, string) error {
+		panic("boom")
+	}})
+}
@@ -0,0 +1,63 @@
+package forms
+
+import (
+	"strings"
+)
+
+func (f *Form) blank(field string) bool {
+	return strings.TrimSpace(f.Get(field)) == ""
+}
+
+// Matches checks that field has the same value as other,
+// e.g. Matches("passwordConfirmation", "password").
+func (f *Form) Matches(field, other string) {
+	if f.Get(field) != f.Get(other) {
+		f.addMessage(field, MsgDoesNotMatch)
+	}
+}
+
+// RequiredIf requires field when otherField has the given value.
+func (f *Form) RequiredIf(field, otherField, value string) {
+	if f.Get(otherField) == value && f.blank(field) {
+		f.addMessage(field, MsgRequired)
+	}
+}
+
+// RequiredWithout requires field when all of the others are blank,
+// e.g. a phone number when no email is given.
+func (f *Form) RequiredWithout(field string, others ...string) {
+	for _, other := range others {
+		if !f.blank(other) {
+			return
+		}
+	}
+	if f.blank(field) {
+		f.addMessage(field, MsgRequired)
+	}
+}
+
+// AtLeastOneOf requires at least one of fields to be filled in, adding
+// the error to each of them otherwise.
+func (f *Form) AtLeastOneOf(fields ...string) {
+	for _, field := range fields {
+		if !f.blank(field) {
+			return
+		}
+	}
+	for _, field := range fields {
+		f.addMessage(field, MsgAtLeastOneOf)
+	}
+}
+
+// UniqueValues checks that a field sent several times, e.g. a multiple
+// select, does not repeat any value.
+func (f *Form) UniqueValues(field string) {
+	seen := map[string]bool{}
+	for _, value := range f.Values[field] {
+		if seen[value] {
+			f.addMessage(field, MsgDuplicateValues)
+			return
+		}
+		seen[value] = true
+	}
+}
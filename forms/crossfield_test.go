@@ -0,0 +1,120 @@
+package forms
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestMatches(t *testing.T) {
+	values := url.Values{}
+	values.Add("password", "secret123")
+	values.Add("passwordConfirmation", "secret321")
+	values.Add("email", "john@test.com")
+	values.Add("emailConfirmation", "john@test.com")
+	form := New(values)
+
+	form.Matches("passwordConfirmation", "password")
+	form.Matches("emailConfirmation", "email")
+
+	actual := form.Errors.Get("passwordConfirmation")
+	expected := "This field does not match"
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+
+	actual = form.Errors.Get("emailConfirmation")
+	expected = ""
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+}
+
+func TestRequiredIf(t *testing.T) {
+	values := url.Values{}
+	values.Add("contact", "phone")
+	values.Add("phone", "")
+	values.Add("email", "")
+	form := New(values)
+
+	form.RequiredIf("phone", "contact", "phone")
+	form.RequiredIf("email", "contact", "email")
+
+	actual := form.Errors.Get("phone")
+	expected := "This field cannot be blank"
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+
+	actual = form.Errors.Get("email")
+	expected = ""
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+}
+
+func TestRequiredWithout(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   url.Values
+		expected string
+	}{
+		{"Both blank", url.Values{"phone": {""}, "email": {" "}}, "This field cannot be blank"},
+		{"Other filled", url.Values{"phone": {""}, "email": {"john@test.com"}}, ""},
+		{"Field filled", url.Values{"phone": {"5555555555"}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := New(tt.values)
+
+			form.RequiredWithout("phone", "email")
+
+			actual := form.Errors.Get("phone")
+			if actual != tt.expected {
+				t.Errorf("Expected %q got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAtLeastOneOf(t *testing.T) {
+	form := New(url.Values{"phone": {""}})
+
+	form.AtLeastOneOf("phone", "email")
+
+	for _, field := range []string{"phone", "email"} {
+		actual := form.Errors.Get(field)
+		expected := "At least one of these fields is required"
+		if actual != expected {
+			t.Errorf("Expected %q got %q", expected, actual)
+		}
+	}
+
+	form = New(url.Values{"email": {"john@test.com"}})
+	form.AtLeastOneOf("phone", "email")
+	if !form.Valid() {
+		t.Errorf("Expected form to be valid got %v", form.Errors)
+	}
+}
+
+func TestUniqueValues(t *testing.T) {
+	values := url.Values{}
+	values["tags"] = []string{"go", "web", "go"}
+	values["categories"] = []string{"news", "tech"}
+	form := New(values)
+
+	form.UniqueValues("tags")
+	form.UniqueValues("categories")
+
+	actual := form.Errors.Get("tags")
+	expected := "This field contains duplicate values"
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+
+	actual = form.Errors.Get("categories")
+	expected = ""
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+}
@@ -0,0 +1,250 @@
+package forms
+
+import (
+	"fmt"
+	"github.com/tomascaslo/godinez/tracing"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeLayout is used to decode time.Time fields without a layout tag.
+const DefaultTimeLayout = "2006-01-02"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// rule validates field in f, param holds the text after "=" in the tag,
+// e.g. "100" for max=100. It returns an error when param is malformed.
+type rule func(f *Form, field, param string) error
+
+var rules = map[string]rule{
+	"required": func(f *Form, field, _ string) error {
+		f.Required(field)
+		return nil
+	},
+	"email": func(f *Form, field, _ string) error {
+		f.MatchesPattern(field, EmailRX)
+		return nil
+	},
+	"max": func(f *Form, field, param string) error {
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return fmt.Errorf("forms: max expects a number, got %q", param)
+		}
+		f.MaxLength(field, n)
+		return nil
+	},
+	"min": func(f *Form, field, param string) error {
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return fmt.Errorf("forms: min expects a number, got %q", param)
+		}
+		f.MinLength(field, n)
+		return nil
+	},
+	"oneof": func(f *Form, field, param string) error {
+		if param == "" {
+			return fmt.Errorf("forms: oneof expects values separated by %q", "|")
+		}
+		f.PermittedValues(field, strings.Split(param, "|")...)
+		return nil
+	},
+}
+
+// Decode parses the request form, multipart or not, and fills the struct
+// pointed to by dst.
+// Fields are matched by their `form:"name"` tag or their name, nested structs
+// use "parent.child" names and slices take every value of a field. Time fields
+// are parsed with their `layout:"..."` tag or DefaultTimeLayout.
+//
+// Fields are then validated with their `validate:"required,max=100,email"` tag.
+// The rules are required, email, min and max (length in characters) and
+// oneof, whose values are separated by "|", e.g. oneof=draft|published.
+// Conversion and validation failures are added to the returned form's Errors,
+// so check Valid() as usual. An error is only returned when the request can't
+// be parsed, dst is not a pointer to a struct or a validate tag is malformed.
+func Decode(r *http.Request, dst interface{}) (*Form, error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("forms: Decode expects a pointer to a struct, got %T", dst)
+	}
+	_, span := tracing.Start(r.Context(), "forms.Decode")
+	defer span.Finish()
+
+	var f *Form
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		var err error
+		if f, err = NewMultipart(r, MaxMemory); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		if !f.Valid() {
+			// The body was too large to be read.
+			traceErrors(span, f)
+			return f, nil
+		}
+		f.Values = r.Form
+	} else {
+		if err := r.ParseForm(); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		f = New(r.Form)
+	}
+
+	if err := f.decode(rv.Elem(), ""); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	traceErrors(span, f)
+	return f, nil
+}
+
+// traceErrors records the outcome of the validation on span.
+func traceErrors(span *tracing.Span, f *Form) {
+	span.SetAttribute("form.valid", f.Valid())
+	span.SetAttribute("form.errors", len(f.Errors))
+}
+
+func (f *Form) decode(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		name := sf.Tag.Get("form")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		name = prefix + name
+		fv := v.Field(i)
+
+		if sf.Type.Kind() == reflect.Struct && sf.Type != timeType {
+			if err := f.decode(fv, name+"."); err != nil {
+				return err
+			}
+			continue
+		}
+
+		values := f.Values[name]
+		if sf.Type.Kind() == reflect.Slice {
+			slice := reflect.MakeSlice(sf.Type, 0, len(values))
+			for _, value := range values {
+				ev := reflect.New(sf.Type.Elem()).Elem()
+				if !f.setValue(ev, name, value, sf.Tag.Get("layout")) {
+					break
+				}
+				slice = reflect.Append(slice, ev)
+			}
+			fv.Set(slice)
+		} else if len(values) > 0 {
+			f.setValue(fv, name, values[0], sf.Tag.Get("layout"))
+		}
+
+		if _, failed := f.Errors[name]; failed {
+			continue
+		}
+		if err := f.validate(name, sf.Tag.Get("validate")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setValue converts value to the type of v, adding an error to field when
+// it can't. Blank values leave v as its zero value. Strings are stored as
+// submitted, so that values such as passwords are not altered, while other
+// types are parsed without the surrounding spaces.
+func (f *Form) setValue(v reflect.Value, field, raw, layout string) bool {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return true
+	}
+
+	switch {
+	case v.Type() == timeType:
+		if layout == "" {
+			layout = DefaultTimeLayout
+		}
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			f.addMessage(field, MsgDate)
+			return false
+		}
+		v.Set(reflect.ValueOf(t))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Bool:
+		b, err := parseBool(value)
+		if err != nil {
+			f.addMessage(field, MsgBoolean)
+			return false
+		}
+		v.SetBool(b)
+	case v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			f.addMessage(field, MsgInteger)
+			return false
+		}
+		v.SetInt(n)
+	case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			f.addMessage(field, MsgPositiveInteger)
+			return false
+		}
+		v.SetUint(n)
+	case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+		n, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			f.addMessage(field, MsgNumber)
+			return false
+		}
+		v.SetFloat(n)
+	default:
+		f.addMessage(field, MsgInvalid)
+		return false
+	}
+	return true
+}
+
+// parseBool also accepts the "on" value sent by checkboxes.
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "on", "yes":
+		return true, nil
+	case "off", "no":
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// validate runs the comma separated rules of a validate tag on field.
+func (f *Form) validate(field, tag string) error {
+	if tag == "" {
+		return nil
+	}
+	for _, r := range strings.Split(tag, ",") {
+		name, param := r, ""
+		if i := strings.Index(r, "="); i >= 0 {
+			name, param = r[:i], r[i+1:]
+		}
+		registryMu.RLock()
+		fn, ok := rules[strings.TrimSpace(name)]
+		registryMu.RUnlock()
+		if !ok {
+			return fmt.Errorf("forms: unknown validation rule %q", name)
+		}
+		if err := fn(f, field, param); err != nil {
+			return fmt.Errorf("%w on field %s", err, field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package forms
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type address struct {
+	City string `form:"city" validate:"required"`
+	Zip  int    `form:"zip"`
+}
+
+type signup struct {
+	Name       string    `form:"name" validate:"required,max=10"`
+	Email      string    `form:"email" validate:"required,email"`
+	Age        int       `form:"age"`
+	Score      float64   `form:"score"`
+	Newsletter bool      `form:"newsletter"`
+	Birthday   time.Time `form:"birthday"`
+	Expires    time.Time `form:"expires" layout:"2006-01-02 15:04"`
+	Tags       []string  `form:"tags"`
+	Ids        []int     `form:"ids"`
+	Plan       string    `form:"plan" validate:"oneof=free|pro"`
+	Address    address   `form:"address"`
+	Ignored    string    `form:"-"`
+	secret     string
+}
+
+func newFormRequest(values url.Values) *http.Request {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestDecode(t *testing.T) {
+	values := url.Values{}
+	values.Set("name", "john")
+	values.Set("email", "john@test.com")
+	values.Set("age", "32")
+	values.Set("score", "9.5")
+	values.Set("newsletter", "on")
+	values.Set("birthday", "1987-03-02")
+	values.Set("expires", "2019-05-01 10:30")
+	values["tags"] = []string{"go", "web"}
+	values["ids"] = []string{"1", "2"}
+	values.Set("plan", "pro")
+	values.Set("address.city", "CDMX")
+	values.Set("address.zip", "11000")
+	values.Set("Ignored", "x")
+
+	var dst signup
+	form, err := Decode(newFormRequest(values), &dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !form.Valid() {
+		t.Errorf("Expected form to be valid got %v", form.Errors)
+	}
+
+	expected := signup{
+		Name:       "john",
+		Email:      "john@test.com",
+		Age:        32,
+		Score:      9.5,
+		Newsletter: true,
+		Birthday:   time.Date(1987, 3, 2, 0, 0, 0, 0, time.UTC),
+		Expires:    time.Date(2019, 5, 1, 10, 30, 0, 0, time.UTC),
+		Tags:       []string{"go", "web"},
+		Ids:        []int{1, 2},
+		Plan:       "pro",
+		Address:    address{"CDMX", 11000},
+	}
+	if !reflect.DeepEqual(dst, expected) {
+		t.Errorf("Expected %+v got %+v", expected, dst)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	values := url.Values{}
+	values.Set("name", "john the eleventh")
+	values.Set("email", "john@")
+	values.Set("age", "thirty")
+	values.Set("birthday", "02/03/1987")
+	values["ids"] = []string{"1", "x"}
+	values.Set("plan", "enterprise")
+
+	var dst signup
+	form, err := Decode(newFormRequest(values), &dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		field    string
+		expected string
+	}{
+		{"name", "This field is too long (maximum is 10 characters)"},
+		{"email", "This field is invalid"},
+		{"age", "This field must be an integer"},
+		{"birthday", "This field must be a valid date"},
+		{"ids", "This field must be an integer"},
+		{"plan", "This field is invalid. Permitted values: free, pro"},
+		{"address.city", "This field cannot be blank"},
+	}
+
+	for _, tt := range tests {
+		actual := form.Errors.Get(tt.field)
+		if actual != tt.expected {
+			t.Errorf("Expected %s error %q got %q", tt.field, tt.expected, actual)
+		}
+	}
+}
+
+func TestDecodeWhitespace(t *testing.T) {
+	values := url.Values{}
+	values.Set("password", "  secret ")
+	values.Set("age", " 32 ")
+	values.Set("name", "   ")
+
+	var dst struct {
+		Password string `form:"password"`
+		Age      int    `form:"age"`
+		Name     string `form:"name"`
+	}
+	form, err := Decode(newFormRequest(values), &dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !form.Valid() {
+		t.Errorf("Expected form to be valid got %v", form.Errors)
+	}
+	if dst.Password != "  secret " {
+		t.Errorf("Expected %q got %q", "  secret ", dst.Password)
+	}
+	if dst.Age != 32 {
+		t.Errorf("Expected %d got %d", 32, dst.Age)
+	}
+	if dst.Name != "" {
+		t.Errorf("Expected %q got %q", "", dst.Name)
+	}
+}
+
+func TestDecodeInvalidDestination(t *testing.T) {
+	var dst signup
+	_, err := Decode(newFormRequest(url.Values{}), dst)
+	if err == nil {
+		t.Error("Expected error for non pointer destination")
+	}
+
+	var unknown struct {
+		Name string `validate:"unknown"`
+	}
+	_, err = Decode(newFormRequest(url.Values{}), &unknown)
+	if err == nil {
+		t.Error("Expected error for unknown validation rule")
+	}
+}
+
+func TestDecodeMalformedRule(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  interface{}
+	}{
+		{"Non numeric max", &struct {
+			Name string `validate:"max=abc"`
+		}{}},
+		{"Empty min", &struct {
+			Name string `validate:"min="`
+		}{}},
+		{"Empty oneof", &struct {
+			Name string `validate:"oneof="`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(newFormRequest(url.Values{"Name": {"Tomas"}}), tt.dst)
+			if err == nil {
+				t.Error("Expected error for malformed validation rule")
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package forms
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MaxMemory is the part of a multipart body Decode keeps in memory, the
+// rest of the uploaded files is streamed to temporary files.
+var MaxMemory int64 = 32 << 20
+
+// MaxMultipartBytes caps the size of the multipart bodies read by
+// NewMultipart and Decode, since they are written to disk before
+// MaxFileSize can run. Zero or less disables the cap.
+var MaxMultipartBytes int64 = 64 << 20
+
+// NewMultipart parses a multipart/form-data request keeping up to
+// maxMemory bytes in memory and returns a form with its values and files.
+// The temporary files are removed by net/http once the handler returns.
+//
+// Bodies larger than MaxMultipartBytes are not read to the end: the form
+// is returned with a MsgBodyTooLarge error under NonFieldErrors instead.
+func NewMultipart(r *http.Request, maxMemory int64) (*Form, error) {
+	if MaxMultipartBytes > 0 {
+		r.Body = http.MaxBytesReader(nil, r.Body, MaxMultipartBytes)
+	}
+	if err := r.ParseMultipartForm(maxMemory); err != nil {
+		var maxBytesError *http.MaxBytesError
+		if stderrors.As(err, &maxBytesError) {
+			f := New(url.Values{})
+			f.addMessage(NonFieldErrors, MsgBodyTooLarge, byteSize(maxBytesError.Limit))
+			return f, nil
+		}
+		return nil, err
+	}
+	f := New(r.PostForm)
+	f.Files = r.MultipartForm.File
+	return f, nil
+}
+
+// File returns the first file uploaded for field, or nil.
+func (f *Form) File(field string) *multipart.FileHeader {
+	fhs := f.Files[field]
+	if len(fhs) == 0 {
+		return nil
+	}
+	return fhs[0]
+}
+
+// FileRequired checks that at least one non-empty file was uploaded for
+// each of fields.
+func (f *Form) FileRequired(fields ...string) {
+	for _, field := range fields {
+		fh := f.File(field)
+		if fh == nil || fh.Size == 0 {
+			f.addMessage(field, MsgFileRequired)
+		}
+	}
+}
+
+// MaxFileSize checks that every file uploaded for field has at most
+// size bytes.
+func (f *Form) MaxFileSize(field string, size int64) {
+	for _, fh := range f.Files[field] {
+		if fh.Size > size {
+			f.addMessage(field, MsgFileTooLarge, byteSize(size))
+			return
+		}
+	}
+}
+
+// MaxFiles checks that no more than n files were uploaded for field.
+func (f *Form) MaxFiles(field string, n int) {
+	if len(f.Files[field]) > n {
+		f.addMessage(field, MsgTooManyFiles, n)
+	}
+}
+
+// AllowedMIMETypes checks the type of every file uploaded for field,
+// sniffed from its content instead of trusting the file name or the type
+// sent by the browser. Types may use wildcards, e.g. "image/*".
+func (f *Form) AllowedMIMETypes(field string, types ...string) {
+	for _, fh := range f.Files[field] {
+		mediaType, err := DetectFileType(fh)
+		if err != nil || !allowedType(mediaType, types) {
+			f.addMessage(field, MsgFileType, strings.Join(types, ", "))
+			return
+		}
+	}
+}
+
+// DetectFileType sniffs the media type of an uploaded file from its
+// first 512 bytes, e.g. "image/png".
+func DetectFileType(fh *multipart.FileHeader) (string, error) {
+	file, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(buf[:n]))
+	return mediaType, err
+}
+
+func allowedType(mediaType string, types []string) bool {
+	for _, t := range types {
+		if t == mediaType {
+			return true
+		}
+		if strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(t, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// byteSize formats n bytes for messages, e.g. 2097152 as "2 MB" and 1536
+// as "1.5 KB".
+func byteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	size := strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/float64(div)), ".0")
+	return fmt.Sprintf("%s %cB", size, "KMGTPE"[exp])
+}
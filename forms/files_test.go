@@ -0,0 +1,172 @@
+package forms
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+type upload struct {
+	field    string
+	filename string
+	content  []byte
+}
+
+func newMultipartRequest(t *testing.T, values map[string]string, uploads ...upload) *http.Request {
+	body := new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+	for k, v := range values {
+		if err := mw.WriteField(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, u := range uploads {
+		w, err := mw.CreateFormFile(u.field, u.filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(u.content)
+	}
+	mw.Close()
+
+	req := httptest.NewRequest("POST", "/", body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestNewMultipart(t *testing.T) {
+	req := newMultipartRequest(t,
+		map[string]string{"title": "Holidays"},
+		upload{"photo", "beach.png", pngHeader},
+	)
+
+	// Keep nothing in memory so the file goes to a temporary file
+	form, err := NewMultipart(req, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer req.MultipartForm.RemoveAll()
+
+	if actual := form.Get("title"); actual != "Holidays" {
+		t.Errorf("Expected %q got %q", "Holidays", actual)
+	}
+
+	fh := form.File("photo")
+	if fh == nil {
+		t.Fatal("Expected photo to be uploaded")
+	}
+	if fh.Filename != "beach.png" {
+		t.Errorf("Expected %q got %q", "beach.png", fh.Filename)
+	}
+
+	mediaType, err := DetectFileType(fh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mediaType != "image/png" {
+		t.Errorf("Expected %q got %q", "image/png", mediaType)
+	}
+}
+
+func TestFileValidators(t *testing.T) {
+	req := newMultipartRequest(t, nil,
+		upload{"avatar", "avatar.png", pngHeader},
+		upload{"resume", "resume.png", []byte("%PDF-1.4 fake pdf renamed")},
+		upload{"photos", "1.png", pngHeader},
+		upload{"photos", "2.png", pngHeader},
+		upload{"photos", "3.png", pngHeader},
+		upload{"large", "large.txt", []byte(strings.Repeat("a", 2048))},
+	)
+	form, err := NewMultipart(req, MaxMemory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form.FileRequired("avatar", "cover")
+	form.AllowedMIMETypes("avatar", "image/*")
+	form.AllowedMIMETypes("resume", "image/png", "image/jpeg")
+	form.MaxFiles("photos", 2)
+	form.MaxFileSize("large", 1024)
+	form.MaxFileSize("avatar", 1024)
+
+	tests := []struct {
+		field    string
+		expected string
+	}{
+		{"avatar", ""},
+		{"cover", "Please choose a file"},
+		{"resume", "This file type is not allowed. Permitted types: image/png, image/jpeg"},
+		{"photos", "Too many files (maximum is 2)"},
+		{"large", "This file is too large (maximum is 1 KB)"},
+	}
+
+	for _, tt := range tests {
+		actual := form.Errors.Get(tt.field)
+		if actual != tt.expected {
+			t.Errorf("Expected %s error %q got %q", tt.field, tt.expected, actual)
+		}
+	}
+}
+
+func TestDecodeMultipart(t *testing.T) {
+	req := newMultipartRequest(t,
+		map[string]string{"name": "john", "email": "john@test.com"},
+		upload{"avatar", "avatar.png", pngHeader},
+	)
+
+	var dst signup
+	form, err := Decode(req, &dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dst.Name != "john" {
+		t.Errorf("Expected %q got %q", "john", dst.Name)
+	}
+	if form.File("avatar") == nil {
+		t.Error("Expected avatar to be uploaded")
+	}
+}
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		n        int64
+		expected string
+	}{
+		{512, "512 B"},
+		{1024, "1 KB"},
+		{1536, "1.5 KB"},
+		{1000 * 1024, "1000 KB"},
+		{1023 * 1024, "1023 KB"},
+		{2 << 20, "2 MB"},
+		{5 << 30, "5 GB"},
+	}
+
+	for _, tt := range tests {
+		actual := byteSize(tt.n)
+		if actual != tt.expected {
+			t.Errorf("Expected %q got %q", tt.expected, actual)
+		}
+	}
+}
+
+func TestNewMultipartTooLarge(t *testing.T) {
+	defer func(max int64) { MaxMultipartBytes = max }(MaxMultipartBytes)
+	MaxMultipartBytes = 1024
+
+	r := newMultipartRequest(t, map[string]string{"name": "Tomas"},
+		upload{"avatar", "avatar.png", bytes.Repeat([]byte("a"), 4096)})
+	form, err := NewMultipart(r, MaxMemory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Request body must not be larger than 1 KB"
+	if actual := form.Errors.Get(NonFieldErrors); actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+}
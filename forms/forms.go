@@ -1,7 +1,7 @@
 package forms
 
 import (
-	"fmt"
+	"mime/multipart"
 	"net/url"
 	"regexp"
 	"strings"
@@ -13,12 +13,19 @@ var EmailRX = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~]+@[a-zA-Z0-9]
 type Form struct {
 	url.Values
 	Errors errors
+	// Files holds the uploaded files of forms created with NewMultipart.
+	Files map[string][]*multipart.FileHeader
+	// Translator translates error messages, English by default.
+	Translator Translator
+	parsed     map[parsedKey]interface{}
+	// internalErrs are the errors AddError did not show, see Err.
+	internalErrs []error
 }
 
 func New(data url.Values) *Form {
 	return &Form{
-		data,
-		errors(map[string][]string{}),
+		Values: data,
+		Errors: errors(map[string][]string{}),
 	}
 }
 
@@ -26,7 +33,7 @@ func (f *Form) Required(fields ...string) {
 	for _, field := range fields {
 		value := f.Get(field)
 		if strings.TrimSpace(value) == "" {
-			f.Errors.Add(field, "This field cannot be blank")
+			f.addMessage(field, MsgRequired)
 		}
 	}
 }
@@ -37,7 +44,7 @@ func (f *Form) MaxLength(field string, d int) {
 		return
 	}
 	if utf8.RuneCountInString(value) > d {
-		f.Errors.Add(field, fmt.Sprintf("This field is too long (maximum is %d characters)", d))
+		f.addMessage(field, MsgMaxLength, d)
 	}
 }
 
@@ -51,7 +58,7 @@ func (f *Form) PermittedValues(field string, opts ...string) {
 			return
 		}
 	}
-	f.Errors.Add(field, fmt.Sprintf("This field is invalid. Permitted %v", opts))
+	f.addMessage(field, MsgPermittedValues, strings.Join(opts, ", "))
 }
 
 func (f *Form) Valid() bool {
@@ -64,7 +71,7 @@ func (f *Form) MinLength(field string, d int) {
 		return
 	}
 	if utf8.RuneCountInString(value) < d {
-		f.Errors.Add(field, fmt.Sprintf("This field is too short (minimum is %d characters)", d))
+		f.addMessage(field, MsgMinLength, d)
 	}
 }
 
@@ -74,6 +81,6 @@ func (f *Form) MatchesPattern(field string, pattern *regexp.Regexp) {
 		return
 	}
 	if !pattern.MatchString(value) {
-		f.Errors.Add(field, "This field is invalid")
+		f.addMessage(field, MsgInvalid)
 	}
 }
@@ -1,7 +1,6 @@
 package forms
 
 import (
-	"fmt"
 	"net/url"
 	"reflect"
 	"testing"
@@ -10,7 +9,7 @@ import (
 func TestNew(t *testing.T) {
 	values := url.Values{}
 	values.Add("name", "john")
-	expectedForm := &Form{values, errors(map[string][]string{})}
+	expectedForm := &Form{Values: values, Errors: errors(map[string][]string{})}
 	form := New(values)
 
 	if !reflect.DeepEqual(form, expectedForm) {
@@ -79,7 +78,7 @@ func TestPermittedValues(t *testing.T) {
 	}
 
 	actual = form.Errors.Get("occupation")
-	expected = fmt.Sprintf("This field is invalid. Permitted %v", occupationPermittedValues)
+	expected = "This field is invalid. Permitted values: software engineer, data analyst"
 	if actual != expected {
 		t.Errorf("Expected %q got %q", expected, actual)
 	}
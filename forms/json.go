@@ -0,0 +1,162 @@
+package forms
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"github.com/tomascaslo/godinez/tracing"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// NonFieldErrors is the Errors key for problems with the whole body,
+// such as malformed JSON.
+const NonFieldErrors = "_body"
+
+// MaxJSONBytes is the largest request body DecodeJSON reads.
+var MaxJSONBytes int64 = 1 << 20
+
+// DecodeJSON decodes a single JSON object from the request body into the
+// struct pointed to by dst, rejecting unknown fields and bodies larger
+// than MaxJSONBytes. Fields are then validated with their validate tag as
+// in Decode, using the names of their json tags.
+//
+// Problems with the body are added to the returned form's Errors, under
+// the offending field or NonFieldErrors, so they can be rendered like any
+// other form. An error is only returned when dst is not a pointer to a struct.
+func DecodeJSON(r *http.Request, dst interface{}) (*Form, error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("forms: DecodeJSON expects a pointer to a struct, got %T", dst)
+	}
+
+	_, span := tracing.Start(r.Context(), "forms.DecodeJSON")
+	defer span.Finish()
+
+	f := New(map[string][]string{})
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, MaxJSONBytes))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		f.addJSONError(err)
+		traceErrors(span, f)
+		return f, nil
+	}
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		var maxBytesError *http.MaxBytesError
+		if stderrors.As(err, &maxBytesError) {
+			f.addMessage(NonFieldErrors, MsgBodyTooLarge, byteSize(maxBytesError.Limit))
+		} else {
+			f.addMessage(NonFieldErrors, MsgSingleJSONValue)
+		}
+		traceErrors(span, f)
+		return f, nil
+	}
+
+	if err := f.validateJSON(rv.Elem(), ""); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	traceErrors(span, f)
+	return f, nil
+}
+
+func (f *Form) addJSONError(err error) {
+	var syntaxError *json.SyntaxError
+	var typeError *json.UnmarshalTypeError
+	var maxBytesError *http.MaxBytesError
+
+	switch {
+	case stderrors.As(err, &syntaxError):
+		f.addMessage(NonFieldErrors, MsgJSONSyntax, syntaxError.Offset)
+	case stderrors.Is(err, io.ErrUnexpectedEOF):
+		f.addMessage(NonFieldErrors, MsgJSONIncomplete)
+	case stderrors.Is(err, io.EOF):
+		f.addMessage(NonFieldErrors, MsgEmptyBody)
+	case stderrors.As(err, &typeError):
+		if typeError.Field == "" {
+			f.addMessage(NonFieldErrors, MsgJSONObject)
+			return
+		}
+		f.addMessage(typeError.Field, MsgJSONType, jsonTypeName(typeError.Type))
+	case stderrors.As(err, &maxBytesError):
+		f.addMessage(NonFieldErrors, MsgBodyTooLarge, byteSize(maxBytesError.Limit))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no error type for unknown fields
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		f.addMessage(field, MsgUnknownField)
+	default:
+		f.addMessage(NonFieldErrors, MsgInvalid)
+	}
+}
+
+// jsonTypeName describes the JSON type expected for t.
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return "string"
+}
+
+// validateJSON copies the decoded values into f.Values, so the rules used
+// for url encoded forms apply, and runs the validate tags. Zero values are
+// left out so that required rejects them.
+func (f *Form) validateJSON(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		name = prefix + name
+		fv := reflect.Indirect(v.Field(i))
+
+		if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+			if err := f.validateJSON(fv, name+"."); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if fv.IsValid() && !fv.IsZero() {
+			if fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array {
+				for j := 0; j < fv.Len(); j++ {
+					f.Add(name, jsonValueString(fv.Index(j)))
+				}
+			} else {
+				f.Set(name, jsonValueString(fv))
+			}
+		}
+
+		if err := f.validate(name, sf.Tag.Get("validate")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func jsonValueString(v reflect.Value) string {
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,90 @@
+package forms
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type apiAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type apiSignup struct {
+	Name    string     `json:"name" validate:"required,max=10"`
+	Email   string     `json:"email" validate:"required,email"`
+	Age     int        `json:"age"`
+	Tags    []string   `json:"tags" validate:"oneof=go|web"`
+	Address apiAddress `json:"address"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	body := `{"name": "john", "email": "john@test.com", "age": 32, "tags": ["go"], "address": {"city": "CDMX"}}`
+	req := httptest.NewRequest("POST", "/api/users", strings.NewReader(body))
+
+	var dst apiSignup
+	form, err := DecodeJSON(req, &dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !form.Valid() {
+		t.Errorf("Expected form to be valid got %v", form.Errors)
+	}
+	if dst.Name != "john" || dst.Age != 32 || dst.Address.City != "CDMX" {
+		t.Errorf("Unexpected decoded value %+v", dst)
+	}
+	if actual := form.Get("address.city"); actual != "CDMX" {
+		t.Errorf("Expected %q got %q", "CDMX", actual)
+	}
+}
+
+func TestDecodeJSONErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		expectedField string
+		expected      string
+	}{
+		{"Empty body", ``, NonFieldErrors, "Request body must not be empty"},
+		{"Syntax error", `{"name": "john",}`, NonFieldErrors, "Request body contains malformed JSON (at character 17)"},
+		{"Incomplete JSON", `{"name": "john"`, NonFieldErrors, "Request body contains malformed JSON"},
+		{"Not an object", `["john"]`, NonFieldErrors, "Request body must be a JSON object"},
+		{"Wrong type", `{"age": "thirty"}`, "age", "This field must be a number"},
+		{"Nested wrong type", `{"address": {"city": 1}}`, "address.city", "This field must be a string"},
+		{"Unknown field", `{"nickname": "johnny"}`, "nickname", "This field is not allowed"},
+		{"Multiple values", `{"name": "john"} {"name": "jane"}`, NonFieldErrors, "Request body must only contain a single JSON object"},
+		{"Too large", `{"name": "` + strings.Repeat("a", 2048) + `"}`, NonFieldErrors, "Request body must not be larger than 1 KB"},
+		{"Validation", `{"name": "john the eleventh", "email": "john@test.com", "address": {"city": "CDMX"}}`, "name", "This field is too long (maximum is 10 characters)"},
+		{"Nested validation", `{"name": "john", "email": "john@test.com"}`, "address.city", "This field cannot be blank"},
+		{"Slice validation", `{"name": "john", "email": "john@test.com", "tags": ["rust"], "address": {"city": "CDMX"}}`, "tags", "This field is invalid. Permitted values: go, web"},
+	}
+
+	MaxJSONBytes = 1024
+	defer func() { MaxJSONBytes = 1 << 20 }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/users", strings.NewReader(tt.body))
+
+			var dst apiSignup
+			form, err := DecodeJSON(req, &dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := form.Errors.Get(tt.expectedField)
+			if actual != tt.expected {
+				t.Errorf("Expected %q got %q (errors %v)", tt.expected, actual, form.Errors)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONInvalidDestination(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{}`))
+	var dst apiSignup
+	if _, err := DecodeJSON(req, dst); err == nil {
+		t.Error("Expected error for non pointer destination")
+	}
+}
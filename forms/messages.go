@@ -0,0 +1,211 @@
+package forms
+
+import (
+	"fmt"
+	"github.com/tomascaslo/godinez/i18n"
+	"net/http"
+	"strings"
+)
+
+// Message keys used by the validators. Catalogs map them to fmt formats
+// whose arguments are documented next to each key.
+const (
+	MsgRequired             = "required"
+	MsgMaxLength            = "max_length"       // maximum characters
+	MsgMinLength            = "min_length"       // minimum characters
+	MsgPermittedValues      = "permitted_values" // comma separated values
+	MsgInvalid              = "invalid"
+	MsgInteger              = "integer"
+	MsgPositiveInteger      = "positive_integer"
+	MsgNumber               = "number"
+	MsgBoolean              = "boolean"
+	MsgBetween              = "between" // min, max
+	MsgDate                 = "date"
+	MsgBefore               = "before" // formatted date
+	MsgAfter                = "after"  // formatted date
+	MsgURL                  = "url"
+	MsgUUID                 = "uuid"
+	MsgDoesNotMatch         = "does_not_match"
+	MsgAtLeastOneOf         = "at_least_one_of"
+	MsgDuplicateValues      = "duplicate_values"
+	MsgValidationFailed     = "validation_failed"
+	MsgTooManyAttempts      = "too_many_attempts" // minutes
+	MsgTooManyAttemptsShort = "too_many_attempts_short"
+	MsgFileRequired         = "file_required"
+	MsgFileTooLarge         = "file_too_large" // formatted size e.g. "2 MB"
+	MsgFileType             = "file_type"      // comma separated types
+	MsgTooManyFiles         = "too_many_files" // maximum files
+	MsgEmptyBody            = "empty_body"
+	MsgBodyTooLarge         = "body_too_large" // formatted size e.g. "1 MB"
+	MsgJSONSyntax           = "json_syntax"    // byte offset
+	MsgJSONIncomplete       = "json_incomplete"
+	MsgJSONObject           = "json_object"
+	MsgJSONType             = "json_type" // JSON type e.g. "number"
+	MsgSingleJSONValue      = "single_json_value"
+	MsgUnknownField         = "unknown_field"
+	MsgDuplicateEmail       = "duplicate_email"
+	MsgInvalidCredentials   = "invalid_credentials"
+)
+
+// Translator turns a message key and its arguments into a message.
+type Translator interface {
+	Translate(key string, args ...interface{}) string
+}
+
+// Catalog is a Translator backed by a map of message keys to fmt formats.
+// Keys missing from the catalog are returned as they are, so plain
+// messages can be used wherever a key is expected.
+type Catalog map[string]string
+
+func (c Catalog) Translate(key string, args ...interface{}) string {
+	format, ok := c[key]
+	if !ok {
+		return key
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+var English = Catalog{
+	MsgRequired:             "This field cannot be blank",
+	MsgMaxLength:            "This field is too long (maximum is %d characters)",
+	MsgMinLength:            "This field is too short (minimum is %d characters)",
+	MsgPermittedValues:      "This field is invalid. Permitted values: %s",
+	MsgInvalid:              "This field is invalid",
+	MsgInteger:              "This field must be an integer",
+	MsgPositiveInteger:      "This field must be a positive integer",
+	MsgNumber:               "This field must be a number",
+	MsgBoolean:              "This field must be true or false",
+	MsgBetween:              "This field must be between %v and %v",
+	MsgDate:                 "This field must be a valid date",
+	MsgBefore:               "This field must be before %s",
+	MsgAfter:                "This field must be after %s",
+	MsgURL:                  "This field must be a valid URL",
+	MsgUUID:                 "This field must be a valid UUID",
+	MsgDoesNotMatch:         "This field does not match",
+	MsgAtLeastOneOf:         "At least one of these fields is required",
+	MsgDuplicateValues:      "This field contains duplicate values",
+	MsgValidationFailed:     "This field could not be validated, please try again",
+	MsgTooManyAttempts:      "Too many attempts, try again in %d minutes",
+	MsgTooManyAttemptsShort: "Too many attempts, try again in 1 minute",
+	MsgFileRequired:         "Please choose a file",
+	MsgFileTooLarge:         "This file is too large (maximum is %s)",
+	MsgFileType:             "This file type is not allowed. Permitted types: %s",
+	MsgTooManyFiles:         "Too many files (maximum is %d)",
+	MsgEmptyBody:            "Request body must not be empty",
+	MsgBodyTooLarge:         "Request body must not be larger than %s",
+	MsgJSONSyntax:           "Request body contains malformed JSON (at character %d)",
+	MsgJSONIncomplete:       "Request body contains malformed JSON",
+	MsgJSONObject:           "Request body must be a JSON object",
+	MsgJSONType:             "This field must be a %s",
+	MsgSingleJSONValue:      "Request body must only contain a single JSON object",
+	MsgUnknownField:         "This field is not allowed",
+	MsgDuplicateEmail:       "Address is already in use",
+	MsgInvalidCredentials:   "Email or password is incorrect",
+}
+
+var Spanish = Catalog{
+	MsgRequired:             "Este campo no puede estar vacío",
+	MsgMaxLength:            "Este campo es demasiado largo (el máximo es %d caracteres)",
+	MsgMinLength:            "Este campo es demasiado corto (el mínimo es %d caracteres)",
+	MsgPermittedValues:      "Este campo no es válido. Valores permitidos: %s",
+	MsgInvalid:              "Este campo no es válido",
+	MsgInteger:              "Este campo debe ser un número entero",
+	MsgPositiveInteger:      "Este campo debe ser un número entero positivo",
+	MsgNumber:               "Este campo debe ser un número",
+	MsgBoolean:              "Este campo debe ser verdadero o falso",
+	MsgBetween:              "Este campo debe estar entre %v y %v",
+	MsgDate:                 "Este campo debe ser una fecha válida",
+	MsgBefore:               "Este campo debe ser anterior a %s",
+	MsgAfter:                "Este campo debe ser posterior a %s",
+	MsgURL:                  "Este campo debe ser una URL válida",
+	MsgUUID:                 "Este campo debe ser un UUID válido",
+	MsgDoesNotMatch:         "Este campo no coincide",
+	MsgAtLeastOneOf:         "Al menos uno de estos campos es obligatorio",
+	MsgDuplicateValues:      "Este campo contiene valores duplicados",
+	MsgValidationFailed:     "No se pudo validar este campo, inténtalo de nuevo",
+	MsgTooManyAttempts:      "Demasiados intentos, inténtalo de nuevo en %d minutos",
+	MsgTooManyAttemptsShort: "Demasiados intentos, inténtalo de nuevo en 1 minuto",
+	MsgFileRequired:         "Por favor elige un archivo",
+	MsgFileTooLarge:         "Este archivo es demasiado grande (el máximo es %s)",
+	MsgFileType:             "Este tipo de archivo no está permitido. Tipos permitidos: %s",
+	MsgTooManyFiles:         "Demasiados archivos (el máximo es %d)",
+	MsgEmptyBody:            "El cuerpo de la solicitud no puede estar vacío",
+	MsgBodyTooLarge:         "El cuerpo de la solicitud no puede ser mayor a %s",
+	MsgJSONSyntax:           "El cuerpo de la solicitud contiene JSON mal formado (en el carácter %d)",
+	MsgJSONIncomplete:       "El cuerpo de la solicitud contiene JSON mal formado",
+	MsgJSONObject:           "El cuerpo de la solicitud debe ser un objeto JSON",
+	MsgJSONType:             "Este campo debe ser de tipo %s",
+	MsgSingleJSONValue:      "El cuerpo de la solicitud solo debe contener un objeto JSON",
+	MsgUnknownField:         "Este campo no está permitido",
+	MsgDuplicateEmail:       "Esta dirección ya está en uso",
+	MsgInvalidCredentials:   "El correo o la contraseña son incorrectos",
+}
+
+// Catalogs holds the built-in catalogs by language. Add entries to support
+// more languages or to override messages.
+var Catalogs = map[string]Catalog{
+	"en": English,
+	"es": Spanish,
+}
+
+// The built-in catalogs are also added to i18n.Default, so that a form
+// using one of its translators, e.g. i18n.Default.Translator("es"), gets
+// the validator messages along with the app's own. Later changes to
+// Catalogs are not copied, add them with i18n.Default.AddMessages too.
+func init() {
+	for locale, c := range Catalogs {
+		i18n.Default.AddMessages(locale, c)
+	}
+}
+
+// DefaultLocale is used when no catalog matches the requested locale.
+var DefaultLocale = "en"
+
+// TranslatorFor returns the catalog for locale, e.g. "es" or "es-MX",
+// falling back to DefaultLocale.
+func TranslatorFor(locale string) Translator {
+	locale = strings.ToLower(locale)
+	if c, ok := Catalogs[locale]; ok {
+		return c
+	}
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		if c, ok := Catalogs[locale[:i]]; ok {
+			return c
+		}
+	}
+	return Catalogs[DefaultLocale]
+}
+
+// SetLocale translates the messages of the validators run afterwards.
+func (f *Form) SetLocale(locale string) {
+	f.Translator = TranslatorFor(locale)
+}
+
+// Message translates key with the form's Translator, English by default.
+func (f *Form) Message(key string, args ...interface{}) string {
+	if f.Translator == nil {
+		return TranslatorFor(DefaultLocale).Translate(key, args...)
+	}
+	return f.Translator.Translate(key, args...)
+}
+
+func (f *Form) addMessage(field, key string, args ...interface{}) {
+	f.Errors.Add(field, f.Message(key, args...))
+}
+
+// LocaleFromRequest returns the locale set by the Locale middleware or,
+// without it, the preferred locale of the Accept-Language header of r that
+// has a catalog. DefaultLocale is returned when neither is available.
+func LocaleFromRequest(r *http.Request) string {
+	if locale := i18n.LocaleFromContext(r.Context()); locale != "" {
+		return locale
+	}
+	supported := make([]string, 0, len(Catalogs))
+	for locale := range Catalogs {
+		supported = append(supported, locale)
+	}
+	if locale := i18n.Negotiate(r.Header.Get("Accept-Language"), supported); locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
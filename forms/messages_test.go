@@ -0,0 +1,102 @@
+package forms
+
+import (
+	"github.com/tomascaslo/godinez/i18n"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestSetLocale(t *testing.T) {
+	values := url.Values{}
+	values.Add("name", "")
+	values.Add("nickname", "xxxxxxxxxxxxxxx")
+	values.Add("sex", "other")
+	form := New(values)
+	form.SetLocale("es-MX")
+
+	form.Required("name")
+	form.MaxLength("nickname", 10)
+	form.PermittedValues("sex", "male", "female")
+
+	tests := []struct {
+		field    string
+		expected string
+	}{
+		{"name", "Este campo no puede estar vacío"},
+		{"nickname", "Este campo es demasiado largo (el máximo es 10 caracteres)"},
+		{"sex", "Este campo no es válido. Valores permitidos: male, female"},
+	}
+
+	for _, tt := range tests {
+		actual := form.Errors.Get(tt.field)
+		if actual != tt.expected {
+			t.Errorf("Expected %s error %q got %q", tt.field, tt.expected, actual)
+		}
+	}
+}
+
+func TestBundleTranslator(t *testing.T) {
+	i18n.Default.AddMessages("es", map[string]string{"signup.title": "Regístrate"})
+	form := New(url.Values{})
+	form.Translator = i18n.Default.Translator("es-MX")
+
+	form.Required("name")
+	form.MaxLength("name", 0)
+
+	expected := "Este campo no puede estar vacío"
+	if actual := form.Errors.Get("name"); actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+	if actual := form.Message("signup.title"); actual != "Regístrate" {
+		t.Errorf("Expected %q got %q", "Regístrate", actual)
+	}
+}
+
+func TestCatalogTranslate(t *testing.T) {
+	c := Catalog{"greeting": "Hello %s"}
+
+	actual := c.Translate("greeting", "John")
+	if actual != "Hello John" {
+		t.Errorf("Expected %q got %q", "Hello John", actual)
+	}
+
+	actual = c.Translate("This field is reserved")
+	if actual != "This field is reserved" {
+		t.Errorf("Expected %q got %q", "This field is reserved", actual)
+	}
+}
+
+func TestCatalogsAreComplete(t *testing.T) {
+	for key := range English {
+		if _, ok := Spanish[key]; !ok {
+			t.Errorf("Spanish catalog is missing %q", key)
+		}
+	}
+}
+
+func TestLocaleFromRequest(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{"No header", "", "en"},
+		{"Spanish", "es-MX,es;q=0.9,en;q=0.8", "es"},
+		{"English preferred", "en-US,es;q=0.5", "en"},
+		{"Unsupported language", "fr-FR", "en"},
+		{"Quality order", "fr;q=1, es;q=0.7, en;q=0.3", "es"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+
+			actual := LocaleFromRequest(req)
+			if actual != tt.expected {
+				t.Errorf("Expected %q got %q", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package forms
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var UUIDRX = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+
+// parsedKey identifies a field converted to a kind of value such as "int".
+// Times include their layout in kind, e.g. "time 2006-01-02".
+type parsedKey struct {
+	field string
+	kind  string
+}
+
+// parse returns the value of field converted by fn, caching the result so
+// that validators and getters only parse each field once. The cache is not
+// cleared by f.Set, so values changed after validating are not parsed again.
+func (f *Form) parse(field, kind string, fn func(string) (interface{}, error)) (interface{}, bool) {
+	key := parsedKey{field, kind}
+	if v, ok := f.parsed[key]; ok {
+		if _, failed := v.(error); !failed {
+			return v, true
+		}
+	}
+	value := strings.TrimSpace(f.Get(field))
+	v, err := fn(value)
+	if f.parsed == nil {
+		f.parsed = map[parsedKey]interface{}{}
+	}
+	if err != nil {
+		f.parsed[key] = err
+		return nil, false
+	}
+	f.parsed[key] = v
+	return v, true
+}
+
+func parseInt(value string) (interface{}, error) {
+	return strconv.Atoi(value)
+}
+
+func parseFloat(value string) (interface{}, error) {
+	return strconv.ParseFloat(value, 64)
+}
+
+func parseBoolValue(value string) (interface{}, error) {
+	return parseBool(value)
+}
+
+func parseTime(layout string) func(string) (interface{}, error) {
+	return func(value string) (interface{}, error) {
+		return time.Parse(layout, value)
+	}
+}
+
+// parseDate parses field with layout, remembering the layout for GetTime
+// when it succeeds.
+func (f *Form) parseDate(field, layout string) (interface{}, bool) {
+	v, ok := f.parse(field, "time "+layout, parseTime(layout))
+	if ok {
+		f.parsed[parsedKey{field, "layout"}] = layout
+	}
+	return v, ok
+}
+
+func (f *Form) IsInt(field string) {
+	if f.Get(field) == "" {
+		return
+	}
+	if _, ok := f.parse(field, "int", parseInt); !ok {
+		f.addMessage(field, MsgInteger)
+	}
+}
+
+func (f *Form) IsFloat(field string) {
+	if f.Get(field) == "" {
+		return
+	}
+	if _, ok := f.parse(field, "float", parseFloat); !ok {
+		f.addMessage(field, MsgNumber)
+	}
+}
+
+func (f *Form) IsBool(field string) {
+	if f.Get(field) == "" {
+		return
+	}
+	if _, ok := f.parse(field, "bool", parseBoolValue); !ok {
+		f.addMessage(field, MsgBoolean)
+	}
+}
+
+// Between checks that field is a number from min to max, both inclusive.
+func (f *Form) Between(field string, min, max float64) {
+	if f.Get(field) == "" {
+		return
+	}
+	v, ok := f.parse(field, "float", parseFloat)
+	if !ok {
+		f.addMessage(field, MsgNumber)
+		return
+	}
+	if n := v.(float64); n < min || n > max {
+		f.addMessage(field, MsgBetween, min, max)
+	}
+}
+
+// IsDate checks that field is a date in the given layout, e.g. "2006-01-02".
+func (f *Form) IsDate(field, layout string) {
+	if f.Get(field) == "" {
+		return
+	}
+	if _, ok := f.parseDate(field, layout); !ok {
+		f.addMessage(field, MsgDate)
+	}
+}
+
+// Before checks that field is a date in the given layout before t.
+func (f *Form) Before(field, layout string, t time.Time) {
+	if f.Get(field) == "" {
+		return
+	}
+	v, ok := f.parseDate(field, layout)
+	if !ok {
+		f.addMessage(field, MsgDate)
+		return
+	}
+	if !v.(time.Time).Before(t) {
+		f.addMessage(field, MsgBefore, t.Format(layout))
+	}
+}
+
+// After checks that field is a date in the given layout after t.
+func (f *Form) After(field, layout string, t time.Time) {
+	if f.Get(field) == "" {
+		return
+	}
+	v, ok := f.parseDate(field, layout)
+	if !ok {
+		f.addMessage(field, MsgDate)
+		return
+	}
+	if !v.(time.Time).After(t) {
+		f.addMessage(field, MsgAfter, t.Format(layout))
+	}
+}
+
+// IsURL checks that field is an absolute http or https URL.
+func (f *Form) IsURL(field string) {
+	value := f.Get(field)
+	if value == "" {
+		return
+	}
+	u, err := url.ParseRequestURI(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		f.addMessage(field, MsgURL)
+	}
+}
+
+func (f *Form) IsUUID(field string) {
+	value := f.Get(field)
+	if value == "" {
+		return
+	}
+	if !UUIDRX.MatchString(value) {
+		f.addMessage(field, MsgUUID)
+	}
+}
+
+// GetInt returns field as an int, or 0 if it is not an integer.
+func (f *Form) GetInt(field string) int {
+	v, ok := f.parse(field, "int", parseInt)
+	if !ok {
+		return 0
+	}
+	return v.(int)
+}
+
+// GetFloat returns field as a float64, or 0 if it is not a number.
+func (f *Form) GetFloat(field string) float64 {
+	v, ok := f.parse(field, "float", parseFloat)
+	if !ok {
+		return 0
+	}
+	return v.(float64)
+}
+
+// GetBool returns field as a bool, checkboxes sending "on" are true.
+func (f *Form) GetBool(field string) bool {
+	v, ok := f.parse(field, "bool", parseBoolValue)
+	if !ok {
+		return false
+	}
+	return v.(bool)
+}
+
+// GetTime returns the date validated with IsDate, Before or After, with
+// the layout of the last of them that succeeded, parsing it with
+// DefaultTimeLayout otherwise. The zero time is returned if the
+// field is not a date.
+func (f *Form) GetTime(field string) time.Time {
+	layout := DefaultTimeLayout
+	if v, ok := f.parsed[parsedKey{field, "layout"}]; ok {
+		layout = v.(string)
+	}
+	v, ok := f.parse(field, "time "+layout, parseTime(layout))
+	if !ok {
+		return time.Time{}
+	}
+	return v.(time.Time)
+}
@@ -0,0 +1,150 @@
+package forms
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestNumericValidators(t *testing.T) {
+	values := url.Values{}
+	values.Add("age", "32")
+	values.Add("weight", "70.5")
+	values.Add("nickname", "johnny")
+	values.Add("subscribed", "on")
+	values.Add("admin", "maybe")
+	form := New(values)
+
+	form.IsInt("age")
+	form.IsInt("weight")
+	form.IsFloat("weight")
+	form.IsFloat("nickname")
+	form.IsBool("subscribed")
+	form.IsBool("admin")
+	form.Between("age", 18, 30)
+	form.Between("weight", 50, 100)
+	form.IsInt("missing")
+
+	tests := []struct {
+		field    string
+		expected string
+	}{
+		{"age", "This field must be between 18 and 30"},
+		{"weight", "This field must be an integer"},
+		{"nickname", "This field must be a number"},
+		{"subscribed", ""},
+		{"admin", "This field must be true or false"},
+		{"missing", ""},
+	}
+
+	for _, tt := range tests {
+		actual := form.Errors.Get(tt.field)
+		if actual != tt.expected {
+			t.Errorf("Expected %s error %q got %q", tt.field, tt.expected, actual)
+		}
+	}
+
+	if len(form.Errors["weight"]) != 1 {
+		t.Errorf("Expected weight to only fail IsInt got %v", form.Errors["weight"])
+	}
+}
+
+func TestDateValidators(t *testing.T) {
+	values := url.Values{}
+	values.Add("birthday", "1987-03-02")
+	values.Add("starts", "2019-05-01")
+	values.Add("ends", "01/05/2019")
+	values.Add("due", "2019-04-01")
+	form := New(values)
+
+	now := time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)
+	form.IsDate("birthday", "2006-01-02")
+	form.Before("birthday", "2006-01-02", now)
+	form.After("starts", "2006-01-02", now)
+	form.IsDate("ends", "2006-01-02")
+	form.IsDate("due", "2006-01-02")
+	form.Before("due", "02/01/2006", now)
+
+	tests := []struct {
+		field    string
+		expected string
+	}{
+		{"birthday", ""},
+		{"starts", "This field must be after 2019-05-01"},
+		{"ends", "This field must be a valid date"},
+		{"due", "This field must be a valid date"},
+	}
+
+	for _, tt := range tests {
+		actual := form.Errors.Get(tt.field)
+		if actual != tt.expected {
+			t.Errorf("Expected %s error %q got %q", tt.field, tt.expected, actual)
+		}
+	}
+}
+
+func TestFormatValidators(t *testing.T) {
+	values := url.Values{}
+	values.Add("website", "https://example.com/about")
+	values.Add("blog", "example.com")
+	values.Add("id", "123e4567-e89b-12d3-a456-426614174000")
+	values.Add("other id", "123e4567")
+	form := New(values)
+
+	form.IsURL("website")
+	form.IsURL("blog")
+	form.IsUUID("id")
+	form.IsUUID("other id")
+
+	tests := []struct {
+		field    string
+		expected string
+	}{
+		{"website", ""},
+		{"blog", "This field must be a valid URL"},
+		{"id", ""},
+		{"other id", "This field must be a valid UUID"},
+	}
+
+	for _, tt := range tests {
+		actual := form.Errors.Get(tt.field)
+		if actual != tt.expected {
+			t.Errorf("Expected %s error %q got %q", tt.field, tt.expected, actual)
+		}
+	}
+}
+
+func TestTypedGetters(t *testing.T) {
+	values := url.Values{}
+	values.Add("age", "32")
+	values.Add("weight", "70.5")
+	values.Add("subscribed", "on")
+	values.Add("birthday", "02/03/1987")
+	values.Add("starts", "2019-05-01")
+	form := New(values)
+
+	if actual := form.GetInt("age"); actual != 32 {
+		t.Errorf("Expected %d got %d", 32, actual)
+	}
+	if actual := form.GetInt("weight"); actual != 0 {
+		t.Errorf("Expected %d got %d", 0, actual)
+	}
+	if actual := form.GetFloat("weight"); actual != 70.5 {
+		t.Errorf("Expected %v got %v", 70.5, actual)
+	}
+	if actual := form.GetBool("subscribed"); actual != true {
+		t.Errorf("Expected %t got %t", true, actual)
+	}
+
+	// GetTime reuses the layout validated with IsDate
+	form.IsDate("birthday", "02/01/2006")
+	expected := time.Date(1987, 3, 2, 0, 0, 0, 0, time.UTC)
+	if actual := form.GetTime("birthday"); !actual.Equal(expected) {
+		t.Errorf("Expected %v got %v", expected, actual)
+	}
+
+	expected = time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)
+	if actual := form.GetTime("starts"); !actual.Equal(expected) {
+		t.Errorf("Expected %v got %v", expected, actual)
+	}
+}
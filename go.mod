@@ -1,8 +1,10 @@
 module github.com/tomascaslo/godinez
 
-go 1.12
+go 1.23
 
 require (
 	github.com/golangcollege/sessions v1.1.0
 	github.com/justinas/nosurf v0.0.0-20190416172904-05988550ea18
 )
+
+require golang.org/x/crypto v0.0.0-20181009213950-7c1a557ab941 // indirect
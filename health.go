@@ -0,0 +1,179 @@
+package godinez
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Checker reports whether a dependency of the app, such as the database,
+// is healthy. It should return once ctx is done.
+type Checker func(ctx context.Context) error
+
+type namedChecker struct {
+	name    string
+	checker Checker
+}
+
+// CheckResult is the outcome of a single Checker in a HealthReport. Error
+// is only set with WithCheckErrors, see runCheck.
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// HealthReport is the JSON body of Healthz and Readyz.
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// ErrShuttingDown is reported by Readyz once the app starts shutting down.
+var ErrShuttingDown = errors.New("shutting down")
+
+// WithCheckTimeout sets how long each Checker may run, 2s by default.
+func WithCheckTimeout(d time.Duration) Option {
+	return func(app *App) {
+		app.checkTimeout = d
+	}
+}
+
+// WithCheckErrors includes the errors of the failed checks in the health
+// reports. They may reveal details of the infrastructure, so they are only
+// logged by default.
+func WithCheckErrors(expose bool) Option {
+	return func(app *App) {
+		app.checkErrors = expose
+	}
+}
+
+// AddCheck registers checker under name, replacing an existing one.
+func (app *App) AddCheck(name string, checker Checker) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	for i := range app.checks {
+		if app.checks[i].name == name {
+			app.checks[i].checker = checker
+			return
+		}
+	}
+	app.checks = append(app.checks, namedChecker{name, checker})
+}
+
+// Healthz runs the registered checks concurrently and responds with a
+// HealthReport, 503 if any of them failed.
+// Use it as mux.HandleFunc("/healthz", app.Healthz).
+func (app *App) Healthz(w http.ResponseWriter, r *http.Request) {
+	app.writeReport(w, app.Check(r.Context()))
+}
+
+// Readyz is Healthz, except that it fails once the app is shutting down so
+// that load balancers stop routing requests to it.
+func (app *App) Readyz(w http.ResponseWriter, r *http.Request) {
+	report := app.Check(r.Context())
+	if app.shuttingDown.Load() {
+		report.Status = "fail"
+		report.Checks["shutdown"] = CheckResult{Status: "fail", Error: ErrShuttingDown.Error(), Duration: "0s"}
+	}
+	app.writeReport(w, report)
+}
+
+// Check runs the registered checks concurrently, each within the check
+// timeout.
+func (app *App) Check(ctx context.Context) HealthReport {
+	app.mu.Lock()
+	checks := append([]namedChecker{}, app.checks...)
+	app.mu.Unlock()
+
+	report := HealthReport{Status: "ok", Checks: make(map[string]CheckResult, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range checks {
+		wg.Add(1)
+		go func(c namedChecker) {
+			defer wg.Done()
+			result := app.runCheck(ctx, c.name, c.checker)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[c.name] = result
+			if result.Status != "ok" {
+				report.Status = "fail"
+			}
+		}(c)
+	}
+	wg.Wait()
+	return report
+}
+
+// runCheck runs the check name, logging its error.
+func (app *App) runCheck(ctx context.Context, name string, checker Checker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, app.checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() { errCh <- checker(ctx) }()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{Status: "ok", Duration: time.Since(start).Round(time.Millisecond).String()}
+	if err != nil {
+		result.Status = "fail"
+		app.errorLog.Printf("health check %s: %v", name, err)
+		if app.checkErrors {
+			result.Error = err.Error()
+		}
+	}
+	return result
+}
+
+func (app *App) writeReport(w http.ResponseWriter, report HealthReport) {
+	status := http.StatusOK
+	if report.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		app.errorLog.Output(2, err.Error())
+	}
+}
+
+// PingCheck checks a database, e.g. a *sql.DB.
+func PingCheck(db interface{ PingContext(context.Context) error }) Checker {
+	return db.PingContext
+}
+
+// TemplateCacheCheck fails when the app has no templates or misses any of
+// names, e.g. "home.page.tmpl".
+func (app *App) TemplateCacheCheck(names ...string) Checker {
+	return func(ctx context.Context) error {
+		if len(app.templateCache) == 0 {
+			return errors.New("template cache is empty")
+		}
+		missing := []string{}
+		for _, name := range names {
+			if _, err := app.GetTemplateCache(name); err != nil {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return fmt.Errorf("missing templates: %s", strings.Join(missing, ", "))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd)
+
+package godinez
+
+import (
+	"context"
+	"errors"
+)
+
+// DiskSpaceCheck always fails on this platform, since the available disk
+// space can only be read on linux, darwin and freebsd.
+func DiskSpaceCheck(path string, minFree uint64) Checker {
+	return func(ctx context.Context) error {
+		return errors.New("disk space check not supported on this platform")
+	}
+}
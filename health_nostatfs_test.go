@@ -0,0 +1,14 @@
+//go:build !(linux || darwin || freebsd)
+
+package godinez
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDiskSpaceCheckNotSupported(t *testing.T) {
+	if err := DiskSpaceCheck(".", 1)(context.Background()); err == nil {
+		t.Error("Expected an error on an unsupported platform")
+	}
+}
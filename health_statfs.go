@@ -0,0 +1,25 @@
+//go:build linux || darwin || freebsd
+
+package godinez
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+)
+
+// DiskSpaceCheck fails when the file system holding path has less than
+// minFree bytes available, e.g. DiskSpaceCheck("/var/lib/app", 1<<30).
+func DiskSpaceCheck(path string, minFree uint64) Checker {
+	return func(ctx context.Context) error {
+		var st syscall.Statfs_t
+		if err := syscall.Statfs(path, &st); err != nil {
+			return err
+		}
+		free := uint64(st.Bavail) * uint64(st.Bsize)
+		if free < minFree {
+			return fmt.Errorf("%d bytes free on %s, expected at least %d", free, path, minFree)
+		}
+		return nil
+	}
+}
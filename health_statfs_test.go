@@ -0,0 +1,31 @@
+//go:build linux || darwin || freebsd
+
+package godinez
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDiskSpaceCheck(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name          string
+		path          string
+		minFree       uint64
+		expectedError bool
+	}{
+		{"Enough space", dir, 1, false},
+		{"Not enough space", dir, 1 << 62, true},
+		{"Missing path", dir + "/missing", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DiskSpaceCheck(tt.path, tt.minFree)(context.Background())
+			if (err != nil) != tt.expectedError {
+				t.Errorf("Expected error %t got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
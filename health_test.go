@@ -0,0 +1,155 @@
+package godinez
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealthz(t *testing.T) {
+	discard := log.New(ioutil.Discard, "", 0)
+	tests := []struct {
+		name           string
+		checks         map[string]Checker
+		checkErrors    bool
+		expectedStatus int
+		expected       map[string]string
+	}{
+		{"No checks", nil, true, http.StatusOK, map[string]string{}},
+		{
+			"Passing checks",
+			map[string]Checker{"db": func(ctx context.Context) error { return nil }},
+			true,
+			http.StatusOK,
+			map[string]string{"db": "ok"},
+		},
+		{
+			"Failing check",
+			map[string]Checker{
+				"db":    func(ctx context.Context) error { return nil },
+				"cache": func(ctx context.Context) error { return errors.New("connection refused") },
+			},
+			true,
+			http.StatusServiceUnavailable,
+			map[string]string{"db": "ok", "cache": "connection refused"},
+		},
+		{
+			"Hidden errors",
+			map[string]Checker{"cache": func(ctx context.Context) error { return errors.New("dial tcp 10.0.0.3:6379") }},
+			false,
+			http.StatusServiceUnavailable,
+			map[string]string{"cache": "fail"},
+		},
+		{
+			"Timed out check",
+			map[string]Checker{"slow": func(ctx context.Context) error { time.Sleep(time.Second); return nil }},
+			true,
+			http.StatusServiceUnavailable,
+			map[string]string{"slow": context.DeadlineExceeded.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := NewApp(WithErrorLogger(discard), WithCheckTimeout(20*time.Millisecond), WithCheckErrors(tt.checkErrors))
+			for name, c := range tt.checks {
+				app.AddCheck(name, c)
+			}
+			rr := httptest.NewRecorder()
+
+			app.Healthz(rr, httptest.NewRequest("GET", "/healthz", nil))
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected %d got %d", tt.expectedStatus, rr.Code)
+			}
+			var report HealthReport
+			if err := json.NewDecoder(rr.Body).Decode(&report); err != nil {
+				t.Fatal(err)
+			}
+			for name, expected := range tt.expected {
+				actual := report.Checks[name].Status
+				if report.Checks[name].Error != "" {
+					actual = report.Checks[name].Error
+				}
+				if actual != expected {
+					t.Errorf("Expected %q got %q", expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestReadyzDuringShutdown(t *testing.T) {
+	discard := log.New(ioutil.Discard, "", 0)
+	app := NewApp(WithInfoLogger(discard), WithErrorLogger(discard))
+
+	rr := httptest.NewRecorder()
+	app.Readyz(rr, httptest.NewRequest("GET", "/readyz", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected %d got %d", http.StatusOK, rr.Code)
+	}
+
+	app.Shutdown(app.NewServer("", nil))
+
+	rr = httptest.NewRecorder()
+	app.Readyz(rr, httptest.NewRequest("GET", "/readyz", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected %d got %d", http.StatusServiceUnavailable, rr.Code)
+	}
+}
+
+func TestShutdownDrainDelay(t *testing.T) {
+	discard := log.New(ioutil.Discard, "", 0)
+	app := NewApp(WithInfoLogger(discard), WithErrorLogger(discard), WithDrainDelay(100*time.Millisecond))
+	start := time.Now()
+	done := make(chan struct{})
+	go func() {
+		app.Shutdown(app.NewServer("", nil))
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	rr := httptest.NewRecorder()
+	app.Readyz(rr, httptest.NewRequest("GET", "/readyz", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected %d got %d", http.StatusServiceUnavailable, rr.Code)
+	}
+
+	<-done
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected Shutdown to wait 100ms got %s", elapsed)
+	}
+}
+
+func TestTemplateCacheCheck(t *testing.T) {
+	cache := map[string]*template.Template{"home.page.tmpl": template.New("home.page.tmpl")}
+	tests := []struct {
+		name     string
+		app      *App
+		names    []string
+		expected string
+	}{
+		{"Empty cache", NewApp(), nil, "template cache is empty"},
+		{"Loaded", NewApp(WithTemplateCache(cache)), []string{"home.page.tmpl"}, ""},
+		{"Missing template", NewApp(WithTemplateCache(cache)), []string{"login.page.tmpl", "home.page.tmpl"}, "missing templates: login.page.tmpl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := ""
+			if err := tt.app.TemplateCacheCheck(tt.names...)(context.Background()); err != nil {
+				actual = err.Error()
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected %q got %q", tt.expected, actual)
+			}
+		})
+	}
+}
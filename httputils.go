@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"github.com/golangcollege/sessions"
 	"github.com/justinas/nosurf"
+	"github.com/tomascaslo/godinez/metrics"
+	"github.com/tomascaslo/godinez/tracing"
 	"html/template"
 	"log"
 	"net/http"
@@ -25,8 +27,9 @@ type application interface {
 	IsAuthenticated(*http.Request) bool
 }
 
-// TODO: Refactor this to more specific behaviors so that
-// 	there is no need to implement the whole interface.
+// templateData is the original template data interface, still supported
+// by AddDefaultData and Render. New code can implement only the setters it
+// needs, such as CSRFSetter, or use Page.
 type templateData interface {
 	EnableCSRFToken() bool
 	EnableCurrentYear() bool
@@ -55,7 +58,15 @@ func NotFound(w http.ResponseWriter) {
 	ClientError(w, http.StatusNotFound)
 }
 
-func AddDefaultData(app application, td templateData, r *http.Request) {
+// AddDefaultData sets the CSRF token, current year, authentication, flash,
+// locale, time zone and provided data of data through the setters it
+// implements.
+func AddDefaultData(app application, data interface{}, r *http.Request) {
+	td, ok := data.(templateData)
+	if !ok {
+		addCapabilities(app, data, r)
+		return
+	}
 	if td.EnableCSRFToken() {
 		td.SetCSRFToken(nosurf.Token(r))
 	}
@@ -67,19 +78,43 @@ func AddDefaultData(app application, td templateData, r *http.Request) {
 	}
 }
 
-func Render(app application, td templateData, w http.ResponseWriter, r *http.Request, name string) {
+var renderDuration = metrics.Default.NewHistogram("godinez_template_render_duration_seconds", "Template render duration in seconds.", nil, "template")
+
+// Render executes the template name with data, which can be of any type.
+// The default data is added to data implementing the setters of
+// AddDefaultData, while the original templateData is executed through
+// GetTemplateData. Maps receive the values of the registered providers.
+// Calling AddDefaultData before Render is not needed, but keeps the flash.
+// Rendering is traced with a "render" span.
+func Render(app application, data interface{}, w http.ResponseWriter, r *http.Request, name string) {
+	_, span := tracing.Start(r.Context(), "render")
+	span.SetAttribute("template", name)
+	defer span.Finish()
+
 	ts, err := app.GetTemplateCache(name)
 	if err != nil {
+		span.RecordError(err)
 		ServerError(app.GetErrorLogger(), w, fmt.Errorf("The template %s does not exist", name))
 		return
 	}
 
+	if td, ok := data.(templateData); ok {
+		data = td.GetTemplateData()
+		addProvided(data, r)
+	} else {
+		addCapabilities(app, data, r)
+	}
+
 	buf := new(bytes.Buffer)
 
-	err = ts.Execute(buf, td.GetTemplateData())
+	start := time.Now()
+	err = ts.Execute(buf, data)
+	renderDuration.Observe(time.Since(start).Seconds(), name)
+	span.SetAttribute("bytes", buf.Len())
 	if err != nil {
-		fmt.Println("There was an error")
+		span.RecordError(err)
 		ServerError(app.GetErrorLogger(), w, err)
+		return
 	}
 
 	buf.WriteTo(w)
@@ -253,6 +253,7 @@ func TestRender(t *testing.T) {
 	}
 	templ := template.New("index")
 	templ = template.Must(templ.Parse(`<html><head></head><body>{{.Data}}</body>`))
+	brokenTempl := template.Must(template.New("broken").Parse(`<html><head></head><body>{{.Data.Missing}}</body>`))
 	tests := []struct {
 		name      string
 		app       *mockApplication
@@ -280,6 +281,15 @@ func TestRender(t *testing.T) {
 			"",
 			`<html><head></head><body></body>`,
 		},
+		{
+			"Execution error",
+			&mockApplication{funcReturnValues: map[string]interface{}{"getTemplateCache": brokenTempl, "getErrorLog": log.New(ioutil.Discard, "", 0)}},
+			&mockTemplateData{funcReturnValues: map[string]interface{}{"getTemplateData": struct{ Data string }{"Hello World!"}}},
+			httptest.NewRecorder(),
+			req,
+			"",
+			http.StatusText(http.StatusInternalServerError),
+		},
 		{
 			"Template error",
 			&mockApplication{funcReturnValues: map[string]interface{}{"getTemplateCache": nil, "getErrorLog": log.New(ioutil.Discard, "", 0)}},
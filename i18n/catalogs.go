@@ -0,0 +1,98 @@
+package i18n
+
+// Keys used by the godinez date helpers. The time units take the amount
+// and have a ".other" variant for plurals, e.g. "time.hour.other".
+const (
+	HumanDate = "humanDate"
+	JustNow   = "time.justNow"
+	Ago       = "time.ago" // relative amount
+	In        = "time.in"  // relative amount
+	Second    = "time.second"
+	Minute    = "time.minute"
+	Hour      = "time.hour"
+	Day       = "time.day"
+	Month     = "time.month"
+	Year      = "time.year"
+)
+
+var English = map[string]string{
+	HumanDate: "02 Jan 2006 at 15:04",
+
+	JustNow:           "just now",
+	Ago:               "%s ago",
+	In:                "in %s",
+	Second:            "%d second",
+	Second + ".other": "%d seconds",
+	Minute:            "%d minute",
+	Minute + ".other": "%d minutes",
+	Hour:              "%d hour",
+	Hour + ".other":   "%d hours",
+	Day:               "%d day",
+	Day + ".other":    "%d days",
+	Month:             "%d month",
+	Month + ".other":  "%d months",
+	Year:              "%d year",
+	Year + ".other":   "%d years",
+}
+
+var Spanish = map[string]string{
+	HumanDate: "02 Jan 2006 a las 15:04",
+
+	JustNow:           "justo ahora",
+	Ago:               "hace %s",
+	In:                "en %s",
+	Second:            "%d segundo",
+	Second + ".other": "%d segundos",
+	Minute:            "%d minuto",
+	Minute + ".other": "%d minutos",
+	Hour:              "%d hora",
+	Hour + ".other":   "%d horas",
+	Day:               "%d día",
+	Day + ".other":    "%d días",
+	Month:             "%d mes",
+	Month + ".other":  "%d meses",
+	Year:              "%d año",
+	Year + ".other":   "%d años",
+
+	"month.January":   "enero",
+	"month.February":  "febrero",
+	"month.March":     "marzo",
+	"month.April":     "abril",
+	"month.May":       "mayo",
+	"month.June":      "junio",
+	"month.July":      "julio",
+	"month.August":    "agosto",
+	"month.September": "septiembre",
+	"month.October":   "octubre",
+	"month.November":  "noviembre",
+	"month.December":  "diciembre",
+
+	"month.short.Jan": "ene",
+	"month.short.Feb": "feb",
+	"month.short.Mar": "mar",
+	"month.short.Apr": "abr",
+	"month.short.May": "may",
+	"month.short.Jun": "jun",
+	"month.short.Jul": "jul",
+	"month.short.Aug": "ago",
+	"month.short.Sep": "sep",
+	"month.short.Oct": "oct",
+	"month.short.Nov": "nov",
+	"month.short.Dec": "dic",
+
+	"day.Monday":    "lunes",
+	"day.Tuesday":   "martes",
+	"day.Wednesday": "miércoles",
+	"day.Thursday":  "jueves",
+	"day.Friday":    "viernes",
+	"day.Saturday":  "sábado",
+	"day.Sunday":    "domingo",
+
+	"day.short.Mon": "lun",
+	"day.short.Tue": "mar",
+	"day.short.Wed": "mié",
+	"day.short.Thu": "jue",
+	"day.short.Fri": "vie",
+	"day.short.Sat": "sáb",
+	"day.short.Sun": "dom",
+}
@@ -0,0 +1,257 @@
+// Package i18n provides message catalogs, locale negotiation and
+// locale-aware date formatting for templates and forms.
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type contextKey string
+
+var contextKeyLocale = contextKey("locale")
+
+// Bundle holds the message catalogs of every supported locale.
+// Messages are fmt formats, e.g. "Hello %s".
+type Bundle struct {
+	DefaultLocale string
+
+	mu       sync.RWMutex
+	catalogs map[string]map[string]string
+}
+
+// Default is the bundle used by the godinez template functions.
+var Default = NewBundle("en")
+
+// NewBundle initializes a *Bundle with the built-in date names for
+// English and Spanish.
+func NewBundle(defaultLocale string) *Bundle {
+	b := &Bundle{
+		DefaultLocale: defaultLocale,
+		catalogs:      map[string]map[string]string{},
+	}
+	b.AddMessages("en", English)
+	b.AddMessages("es", Spanish)
+	return b
+}
+
+// AddMessages adds msgs to the catalog of locale, replacing existing keys.
+func (b *Bundle) AddMessages(locale string, msgs map[string]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	locale = normalize(locale)
+	c, ok := b.catalogs[locale]
+	if !ok {
+		c = map[string]string{}
+		b.catalogs[locale] = c
+	}
+	for k, v := range msgs {
+		c[k] = v
+	}
+}
+
+// LoadJSON adds the catalogs of the files in fsys matching pattern, e.g.
+// "locales/*.json". The locale is taken from the file name, so
+// locales/es.json holds the Spanish messages. Nested objects are flattened
+// into dotted keys: {"home": {"title": "Inicio"}} defines "home.title".
+func (b *Bundle) LoadJSON(fsys fs.FS, pattern string) error {
+	return b.load(fsys, pattern, func(data []byte, msgs map[string]string) error {
+		var raw map[string]interface{}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		return flatten(msgs, "", raw)
+	})
+}
+
+// load adds the catalogs of the files in fsys matching pattern, parsed by
+// parse into flat messages.
+func (b *Bundle) load(fsys fs.FS, pattern string, parse func(data []byte, msgs map[string]string) error) error {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		msgs := map[string]string{}
+		if err := parse(data, msgs); err != nil {
+			return fmt.Errorf("i18n: %s: %w", file, err)
+		}
+		b.AddMessages(strings.TrimSuffix(path.Base(file), path.Ext(file)), msgs)
+	}
+	return nil
+}
+
+func flatten(dst map[string]string, prefix string, raw map[string]interface{}) error {
+	for k, v := range raw {
+		switch v := v.(type) {
+		case string:
+			dst[prefix+k] = v
+		case map[string]interface{}:
+			if err := flatten(dst, prefix+k+".", v); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %s%s is not a string", prefix, k)
+		}
+	}
+	return nil
+}
+
+// Locales returns the locales that have a catalog, sorted.
+func (b *Bundle) Locales() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	locales := make([]string, 0, len(b.catalogs))
+	for locale := range b.catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Supports reports whether locale has a catalog.
+func (b *Bundle) Supports(locale string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	_, ok := b.catalogs[normalize(locale)]
+	return ok
+}
+
+// Translate formats the message key of locale with args. It falls back to
+// the language of regional locales (es-MX to es), then to DefaultLocale,
+// and finally returns the key itself.
+func (b *Bundle) Translate(locale, key string, args ...interface{}) string {
+	format, ok := b.lookup(locale, key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func (b *Bundle) lookup(locale, key string) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	locale = normalize(locale)
+	for _, l := range []string{locale, language(locale), normalize(b.DefaultLocale)} {
+		if msg, ok := b.catalogs[l][key]; ok {
+			return msg, true
+		}
+	}
+	return "", false
+}
+
+// Translator returns a translator for locale, which satisfies
+// forms.Translator. The forms package adds its messages to Default, so
+// form.Translator = i18n.Default.Translator("es") translates both the
+// app's and the validators' messages.
+func (b *Bundle) Translator(locale string) *Translator {
+	return &Translator{b, locale}
+}
+
+// Translator translates messages of a single locale.
+type Translator struct {
+	bundle *Bundle
+	locale string
+}
+
+func (t *Translator) Translate(key string, args ...interface{}) string {
+	return t.bundle.Translate(t.locale, key, args...)
+}
+
+// FormatTime formats t with layout, translating month and day names with
+// the keys "month.January", "month.short.Jan", "day.Monday" and
+// "day.short.Mon", e.g. "02 ene 2019" for the layout "02 Jan 2006" in Spanish.
+func (b *Bundle) FormatTime(locale string, t time.Time, layout string) string {
+	s := t.Format(layout)
+	month, day := t.Month().String(), t.Weekday().String()
+	replacements := []struct{ token, name, key string }{
+		{"January", month, "month." + month},
+		{"Jan", month[:3], "month.short." + month[:3]},
+		{"Monday", day, "day." + day},
+		{"Mon", day[:3], "day.short." + day[:3]},
+	}
+	for i, r := range replacements {
+		// Layouts with a full name also contain the short token.
+		if !strings.Contains(layout, r.token) || (i%2 == 1 && strings.Contains(layout, replacements[i-1].token)) {
+			continue
+		}
+		if translated, ok := b.lookup(locale, r.key); ok {
+			s = strings.Replace(s, r.name, translated, 1)
+		}
+	}
+	return s
+}
+
+// Negotiate returns the best locale of supported for an Accept-Language
+// header, or an empty string if none matches. Regional tags match their
+// language, so "es-MX" selects "es" when only "es" is supported.
+func Negotiate(acceptLanguage string, supported []string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		tag := normalize(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+		for _, s := range supported {
+			s = normalize(s)
+			if s == tag || s == language(tag) {
+				best, bestQ = s, q
+				break
+			}
+		}
+	}
+	return best
+}
+
+// WithLocale returns a copy of ctx holding locale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKeyLocale, locale)
+}
+
+// LocaleFromContext returns the locale set by WithLocale, usually by the
+// Locale middleware, or an empty string.
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(contextKeyLocale).(string)
+	return locale
+}
+
+// normalize lower cases a locale and uses "-" as separator, es_MX to es-mx.
+func normalize(locale string) string {
+	return strings.ReplaceAll(strings.ToLower(locale), "_", "-")
+}
+
+// language returns the language of a locale, es-mx to es.
+func language(locale string) string {
+	if i := strings.Index(locale, "-"); i > 0 {
+		return locale[:i]
+	}
+	return locale
+}
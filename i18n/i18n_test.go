@@ -0,0 +1,164 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestTranslate(t *testing.T) {
+	b := NewBundle("en")
+	b.AddMessages("en", map[string]string{"greeting": "Hello %s", "bye": "Bye"})
+	b.AddMessages("es", map[string]string{"greeting": "Hola %s"})
+
+	tests := []struct {
+		name     string
+		locale   string
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"English", "en", "greeting", []interface{}{"John"}, "Hello John"},
+		{"Spanish", "es", "greeting", []interface{}{"Juan"}, "Hola Juan"},
+		{"Regional locale", "es-MX", "greeting", []interface{}{"Juan"}, "Hola Juan"},
+		{"Falls back to default locale", "es", "bye", nil, "Bye"},
+		{"Missing key", "es", "missing", nil, "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := b.Translate(tt.locale, tt.key, tt.args...)
+			if actual != tt.expected {
+				t.Errorf("Expected %q got %q", tt.expected, actual)
+			}
+		})
+	}
+
+	actual := b.Translator("es").Translate("greeting", "Ana")
+	if actual != "Hola Ana" {
+		t.Errorf("Expected %q got %q", "Hola Ana", actual)
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/es.json":    {Data: []byte(`{"home": {"title": "Inicio"}, "about": "Acerca de"}`)},
+		"locales/fr.json":    {Data: []byte(`{"home": {"title": "Accueil"}}`)},
+		"locales/README.txt": {Data: []byte(`ignored`)},
+	}
+	b := NewBundle("en")
+
+	if err := b.LoadJSON(fsys, "locales/*.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual := b.Translate("es", "home.title"); actual != "Inicio" {
+		t.Errorf("Expected %q got %q", "Inicio", actual)
+	}
+	if actual := b.Translate("es", "about"); actual != "Acerca de" {
+		t.Errorf("Expected %q got %q", "Acerca de", actual)
+	}
+	if !b.Supports("fr") {
+		t.Error("Expected fr to be supported")
+	}
+
+	fsys["locales/de.json"] = &fstest.MapFile{Data: []byte(`{"count": 1}`)}
+	if err := b.LoadJSON(fsys, "locales/*.json"); err == nil {
+		t.Error("Expected error for non string message")
+	}
+}
+
+func TestLoadTOML(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/es.toml": {Data: []byte(`# Spanish
+about = "Acerca de" # inline comment
+quote = "Di \"hola\"\n"
+
+[home]
+title = 'Inicio #1'
+`)},
+	}
+	b := NewBundle("en")
+
+	if err := b.LoadTOML(fsys, "locales/*.toml"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"about", "Acerca de"},
+		{"quote", "Di \"hola\"\n"},
+		{"home.title", "Inicio #1"},
+	}
+	for _, tt := range tests {
+		if actual := b.Translate("es", tt.key); actual != tt.expected {
+			t.Errorf("Expected %q got %q", tt.expected, actual)
+		}
+	}
+
+	for _, invalid := range []string{`count = 1`, `title = "Inicio`, `title`, `[home`} {
+		fsys["locales/de.toml"] = &fstest.MapFile{Data: []byte(invalid)}
+		if err := b.LoadTOML(fsys, "locales/de.toml"); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	b := NewBundle("en")
+	date := time.Date(2019, time.March, 4, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale   string
+		layout   string
+		expected string
+	}{
+		{"en", "02 Jan 2006", "04 Mar 2019"},
+		{"es", "02 Jan 2006", "04 mar 2019"},
+		{"es", "Monday 02 January 2006", "lunes 04 marzo 2019"},
+		{"es-MX", "Mon 02 Jan", "lun 04 mar"},
+	}
+
+	for _, tt := range tests {
+		actual := b.FormatTime(tt.locale, date, tt.layout)
+		if actual != tt.expected {
+			t.Errorf("Expected %q got %q", tt.expected, actual)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	supported := []string{"en", "es"}
+	tests := []struct {
+		acceptLanguage string
+		expected       string
+	}{
+		{"", ""},
+		{"es-MX,es;q=0.9,en;q=0.8", "es"},
+		{"fr-FR,en;q=0.5", "en"},
+		{"fr-FR", ""},
+		{"en;q=0.2, es;q=0.8", "es"},
+	}
+
+	for _, tt := range tests {
+		actual := Negotiate(tt.acceptLanguage, supported)
+		if actual != tt.expected {
+			t.Errorf("Expected %q got %q", tt.expected, actual)
+		}
+	}
+}
+
+func TestLocaleContext(t *testing.T) {
+	ctx := context.Background()
+	if actual := LocaleFromContext(ctx); actual != "" {
+		t.Errorf("Expected %q got %q", "", actual)
+	}
+
+	ctx = WithLocale(ctx, "es")
+	if actual := LocaleFromContext(ctx); actual != "es" {
+		t.Errorf("Expected %q got %q", "es", actual)
+	}
+}
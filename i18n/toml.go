@@ -0,0 +1,88 @@
+package i18n
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// LoadTOML is LoadJSON for TOML files, e.g. "locales/*.toml". Tables
+// become dotted keys, so
+//
+//	about = "Acerca de"
+//
+//	[home]
+//	title = "Inicio"
+//
+// defines "about" and "home.title". Only string values are supported, in
+// basic ("...") or literal ('...') single line strings.
+func (b *Bundle) LoadTOML(fsys fs.FS, pattern string) error {
+	return b.load(fsys, pattern, parseTOML)
+}
+
+func parseTOML(data []byte, msgs map[string]string) error {
+	prefix := ""
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			end := strings.Index(line, "]")
+			if end < 0 || strings.HasPrefix(line, "[[") {
+				return fmt.Errorf("line %d: invalid table", i+1)
+			}
+			prefix = strings.TrimSpace(line[1:end]) + "."
+			continue
+		}
+
+		eq := strings.Index(line, "=")
+		if eq < 0 {
+			return fmt.Errorf("line %d: expected key = value", i+1)
+		}
+		key := strings.Trim(strings.TrimSpace(line[:eq]), `"`)
+		value, err := parseTOMLString(strings.TrimSpace(line[eq+1:]))
+		if err != nil {
+			return fmt.Errorf("line %d: message %s%s: %w", i+1, prefix, key, err)
+		}
+		msgs[prefix+key] = value
+	}
+	return nil
+}
+
+// parseTOMLString parses a basic or literal string followed by an optional
+// comment.
+func parseTOMLString(s string) (string, error) {
+	if strings.HasPrefix(s, "'") {
+		end := strings.Index(s[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		return s[1 : end+1], checkTOMLTail(s[end+2:])
+	}
+	if !strings.HasPrefix(s, `"`) {
+		return "", fmt.Errorf("is not a string")
+	}
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", err
+			}
+			return value, checkTOMLTail(s[i+1:])
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+func checkTOMLTail(s string) error {
+	s = strings.TrimSpace(s)
+	if s != "" && !strings.HasPrefix(s, "#") {
+		return fmt.Errorf("unexpected %q after the string", s)
+	}
+	return nil
+}
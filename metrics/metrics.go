@@ -0,0 +1,292 @@
+// Package metrics provides counters, gauges and histograms exposed in the
+// Prometheus text exposition format, without external dependencies.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefBuckets are the default histogram buckets, in seconds, suited to
+// request latencies.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Registry holds metrics and writes them in the text exposition format.
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]*metric
+}
+
+// Default is the registry used by godinez.Render and the middleware
+// package.
+var Default = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{metrics: map[string]*metric{}}
+}
+
+type kind string
+
+const (
+	counterKind   kind = "counter"
+	gaugeKind     kind = "gauge"
+	histogramKind kind = "histogram"
+)
+
+// metric is a named metric with a series per combination of label values.
+type metric struct {
+	name    string
+	help    string
+	kind    kind
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64  // counters and gauges
+	counts      []uint64 // histogram buckets, not cumulative
+	sum         float64
+	count       uint64
+}
+
+// register returns the metric called name, creating it if needed. It
+// panics if name is registered with a different type or labels.
+func (reg *Registry) register(name, help string, k kind, buckets []float64, labels []string) *metric {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if m, ok := reg.metrics[name]; ok {
+		if m.kind != k || strings.Join(m.labels, ",") != strings.Join(labels, ",") {
+			panic(fmt.Sprintf("metrics: %s is already registered as a %s with labels %v", name, m.kind, m.labels))
+		}
+		return m
+	}
+	m := &metric{
+		name:    name,
+		help:    help,
+		kind:    k,
+		labels:  append([]string{}, labels...),
+		buckets: buckets,
+		series:  map[string]*series{},
+	}
+	reg.metrics[name] = m
+	return m
+}
+
+// with calls fn with the series of labelValues while holding the lock.
+func (m *metric) with(labelValues []string, fn func(*series)) {
+	if len(labelValues) != len(m.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", m.name, len(m.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string{}, labelValues...)}
+		if m.kind == histogramKind {
+			s.counts = make([]uint64, len(m.buckets))
+		}
+		m.series[key] = s
+	}
+	fn(s)
+}
+
+// Counter is a value that only goes up, such as the number of requests.
+type Counter struct {
+	m *metric
+}
+
+// NewCounter registers a counter with the given label names.
+func (reg *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	return &Counter{reg.register(name, help, counterKind, nil, labels)}
+}
+
+// Inc adds 1 to the series of labelValues, given in the order of the labels.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds v, which must not be negative, to the series of labelValues.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic("metrics: counters cannot decrease")
+	}
+	c.m.with(labelValues, func(s *series) { s.value += v })
+}
+
+// Gauge is a value that goes up and down, such as requests in flight.
+type Gauge struct {
+	m *metric
+}
+
+// NewGauge registers a gauge with the given label names.
+func (reg *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{reg.register(name, help, gaugeKind, nil, labels)}
+}
+
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.m.with(labelValues, func(s *series) { s.value = v })
+}
+
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.m.with(labelValues, func(s *series) { s.value += v })
+}
+
+func (g *Gauge) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+func (g *Gauge) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+// Histogram counts observations, such as latencies, in buckets.
+type Histogram struct {
+	m *metric
+}
+
+// NewHistogram registers a histogram with the given upper bounds, DefBuckets
+// if nil, and label names.
+func (reg *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	buckets = append([]float64{}, buckets...)
+	sort.Float64s(buckets)
+	return &Histogram{reg.register(name, help, histogramKind, buckets, labels)}
+}
+
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.m.with(labelValues, func(s *series) {
+		if i := sort.SearchFloat64s(h.m.buckets, v); i < len(s.counts) {
+			s.counts[i]++
+		}
+		s.sum += v
+		s.count++
+	})
+}
+
+// WriteTo writes every metric in the text exposition format, sorted by
+// name and label values.
+func (reg *Registry) WriteTo(w io.Writer) (int64, error) {
+	reg.mu.Lock()
+	names := make([]string, 0, len(reg.metrics))
+	for name := range reg.metrics {
+		names = append(names, name)
+	}
+	reg.mu.Unlock()
+	sort.Strings(names)
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, name := range names {
+		reg.mu.Lock()
+		m := reg.metrics[name]
+		reg.mu.Unlock()
+		m.write(bw)
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+func (m *metric) write(w *bufio.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fmt.Fprintf(w, "# HELP %s %s\n", m.name, escapeHelp(m.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.kind)
+
+	keys := make([]string, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := m.series[key]
+		if m.kind != histogramKind {
+			fmt.Fprintf(w, "%s%s %s\n", m.name, m.labelPairs(s.labelValues, ""), formatFloat(s.value))
+			continue
+		}
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", m.name, m.labelPairs(s.labelValues, formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", m.name, m.labelPairs(s.labelValues, "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", m.name, m.labelPairs(s.labelValues, ""), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", m.name, m.labelPairs(s.labelValues, ""), s.count)
+	}
+}
+
+// labelPairs formats labels as {a="1",b="2"}, adding le for histogram
+// buckets when not empty.
+func (m *metric) labelPairs(values []string, le string) string {
+	pairs := make([]string, 0, len(values)+1)
+	for i, v := range values {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, m.labels[i], escapeLabel(v)))
+	}
+	if le != "" {
+		pairs = append(pairs, fmt.Sprintf(`le="%s"`, le))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var (
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelReplacer.Replace(s)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// Handler serves the metrics of reg, usually at /metrics.
+func (reg *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		reg.WriteTo(w)
+	})
+}
+
+// Handler serves the metrics of Default.
+func Handler() http.Handler {
+	return Default.Handler()
+}
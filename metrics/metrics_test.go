@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteTo(t *testing.T) {
+	reg := NewRegistry()
+	requests := reg.NewCounter("http_requests_total", "Requests served.", "method", "status")
+	inFlight := reg.NewGauge("http_requests_in_flight", "Requests being served.")
+	latency := reg.NewHistogram("http_request_duration_seconds", "Request latency.", []float64{0.1, 1}, "method")
+
+	requests.Inc("GET", "200")
+	requests.Inc("GET", "200")
+	requests.Add(1, "POST", `5"0\0`)
+	inFlight.Inc()
+	inFlight.Inc()
+	inFlight.Dec()
+	latency.Observe(0.05, "GET")
+	latency.Observe(0.1, "GET")
+	latency.Observe(3, "GET")
+
+	buf := new(bytes.Buffer)
+	if _, err := reg.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# HELP http_request_duration_seconds Request latency.
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{method="GET",le="0.1"} 2
+http_request_duration_seconds_bucket{method="GET",le="1"} 2
+http_request_duration_seconds_bucket{method="GET",le="+Inf"} 3
+http_request_duration_seconds_sum{method="GET"} 3.15
+http_request_duration_seconds_count{method="GET"} 3
+# HELP http_requests_in_flight Requests being served.
+# TYPE http_requests_in_flight gauge
+http_requests_in_flight 1
+# HELP http_requests_total Requests served.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",status="200"} 2
+http_requests_total{method="POST",status="5\"0\\0"} 1
+`
+	if buf.String() != expected {
+		t.Errorf("Expected %q got %q", expected, buf.String())
+	}
+}
+
+func TestRegister(t *testing.T) {
+	reg := NewRegistry()
+	c := reg.NewCounter("jobs_total", "Jobs.", "queue")
+
+	if again := reg.NewCounter("jobs_total", "Jobs.", "queue"); again.m != c.m {
+		t.Errorf("Expected the registered counter")
+	}
+
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"Different type", func() { reg.NewGauge("jobs_total", "Jobs.", "queue") }},
+		{"Different labels", func() { reg.NewCounter("jobs_total", "Jobs.") }},
+		{"Wrong label values", func() { c.Inc() }},
+		{"Negative counter", func() { c.Add(-1, "mail") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected a panic")
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	reg := NewRegistry()
+	reg.NewCounter("jobs_total", "Jobs.").Inc()
+	rr := httptest.NewRecorder()
+
+	reg.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+
+	expected := "text/plain; version=0.0.4; charset=utf-8"
+	if rr.Header().Get("Content-Type") != expected {
+		t.Errorf("Expected %q got %q", expected, rr.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rr.Body.String(), "jobs_total 1\n") {
+		t.Errorf("Expected %q in %q", "jobs_total 1", rr.Body.String())
+	}
+}
@@ -0,0 +1,265 @@
+package middleware
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CompressOptions configures the Compress middleware.
+type CompressOptions struct {
+	// Level is the compression level, defaults to flate.DefaultCompression.
+	Level int
+	// MinSize is the smallest body in bytes worth compressing, defaults to 1024.
+	MinSize int
+	// SkipContentTypes lists content type prefixes that are already
+	// compressed, defaults to images, audio, video and archives.
+	SkipContentTypes []string
+}
+
+var defaultSkipContentTypes = []string{
+	"image/",
+	"audio/",
+	"video/",
+	"font/woff",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/x-bzip2",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+	"application/pdf",
+}
+
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+// Compress compresses responses with gzip or deflate depending on the
+// Accept-Encoding of the request. Bodies smaller than MinSize, responses
+// that already have a Content-Encoding, partial content and skipped
+// content types are sent as they are. Writers are pooled per middleware.
+func Compress(opts CompressOptions) Mw {
+	if opts.Level == 0 {
+		opts.Level = flate.DefaultCompression
+	}
+	if opts.MinSize <= 0 {
+		opts.MinSize = 1024
+	}
+	if opts.SkipContentTypes == nil {
+		opts.SkipContentTypes = defaultSkipContentTypes
+	}
+
+	pools := map[string]*sync.Pool{
+		"gzip": {New: func() interface{} {
+			gw, err := gzip.NewWriterLevel(io.Discard, opts.Level)
+			if err != nil {
+				gw = gzip.NewWriter(io.Discard)
+			}
+			return gw
+		}},
+		"deflate": {New: func() interface{} {
+			fw, err := flate.NewWriter(io.Discard, opts.Level)
+			if err != nil {
+				fw, _ = flate.NewWriter(io.Discard, flate.DefaultCompression)
+			}
+			return fw
+		}},
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				pool:           pools[encoding],
+				opts:           &opts,
+				status:         http.StatusOK,
+			}
+
+			// close is skipped when next panics, so that a buffered partial
+			// body is not sent as a complete response before RecoverPanic
+			// writes the error.
+			next.ServeHTTP(cw, r)
+			cw.close()
+		})
+	}
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding header,
+// preferring gzip and ignoring codings with q=0. "*" only applies to the
+// codings not listed, so "gzip;q=0, *" selects deflate.
+func negotiateEncoding(header string) string {
+	// accepted maps the listed codings to whether their q is above 0.
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		coding := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if coding != "" {
+			accepted[coding] = q > 0
+		}
+	}
+	for _, coding := range []string{"gzip", "deflate"} {
+		if ok, listed := accepted[coding]; listed {
+			if ok {
+				return coding
+			}
+			continue
+		}
+		if accepted["*"] {
+			return coding
+		}
+	}
+	return ""
+}
+
+// compressWriter buffers the beginning of a response until it knows whether
+// it is worth compressing, then either streams it through a pooled
+// compressor or writes it unchanged.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	pool     *sync.Pool
+	opts     *CompressOptions
+
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         []byte
+	cw          compressor
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = status
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	cw.wroteHeader = true
+	if cw.decided {
+		if cw.cw != nil {
+			return cw.cw.Write(p)
+		}
+		return cw.ResponseWriter.Write(p)
+	}
+
+	cw.buf = append(cw.buf, p...)
+	compressible := cw.compressible()
+	if !compressible || len(cw.buf) >= cw.opts.MinSize {
+		if err := cw.decide(compressible); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush sends buffered data to the client. A response that flushes before
+// reaching MinSize is assumed to be streamed and gets compressed.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if err := cw.decide(cw.compressible()); err != nil {
+			return
+		}
+	}
+	if cw.cw != nil {
+		cw.cw.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+func (cw *compressWriter) compressible() bool {
+	h := cw.Header()
+	if cw.status < http.StatusOK || cw.status == http.StatusNoContent ||
+		cw.status == http.StatusNotModified || cw.status == http.StatusPartialContent {
+		return false
+	}
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" {
+		return false
+	}
+
+	contentType := h.Get("Content-Type")
+	if contentType == "" && len(cw.buf) > 0 {
+		contentType = http.DetectContentType(cw.buf)
+		h.Set("Content-Type", contentType)
+	}
+	for _, skip := range cw.opts.SkipContentTypes {
+		if strings.HasPrefix(contentType, skip) {
+			return false
+		}
+	}
+	return true
+}
+
+func (cw *compressWriter) decide(compress bool) error {
+	cw.decided = true
+	if compress {
+		cw.Header().Set("Content-Encoding", cw.encoding)
+		cw.Header().Del("Content-Length")
+		cw.cw = cw.pool.Get().(compressor)
+		cw.cw.Reset(cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if cw.cw != nil {
+		_, err := cw.cw.Write(buf)
+		return err
+	}
+	_, err := cw.ResponseWriter.Write(buf)
+	return err
+}
+
+// close writes what is left once the handler returns. Bodies that never
+// reached MinSize are sent uncompressed.
+func (cw *compressWriter) close() {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			return
+		}
+		cw.decide(false)
+		return
+	}
+	if cw.cw != nil {
+		cw.cw.Close()
+		cw.cw.Reset(io.Discard)
+		cw.pool.Put(cw.cw)
+		cw.cw = nil
+	}
+}
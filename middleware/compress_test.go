@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat("<p>Hello World!</p>", 100)
+	small := "<p>Hello</p>"
+
+	tests := []struct {
+		name             string
+		acceptEncoding   string
+		rangeHeader      string
+		contentType      string
+		contentEncoding  string
+		body             string
+		expectedEncoding string
+	}{
+		{"Gzip", "gzip, deflate", "", "text/html", "", large, "gzip"},
+		{"Deflate", "deflate", "", "text/html", "", large, "deflate"},
+		{"Gzip disabled with q=0", "gzip;q=0, deflate", "", "text/html", "", large, "deflate"},
+		{"No Accept-Encoding", "", "", "text/html", "", large, ""},
+		{"Small body", "gzip", "", "text/html", "", small, ""},
+		{"Already compressed content type", "gzip", "", "image/png", "", large, ""},
+		{"Already encoded", "gzip", "", "text/html", "br", large, "br"},
+		{"Range request", "gzip", "bytes=0-10", "text/html", "", large, ""},
+		{"Sniffed content type", "gzip", "", "", "", large, "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				if tt.contentEncoding != "" {
+					w.Header().Set("Content-Encoding", tt.contentEncoding)
+				}
+				// Write in chunks to exercise buffering
+				for i := 0; i < len(tt.body); i += 100 {
+					end := i + 100
+					if end > len(tt.body) {
+						end = len(tt.body)
+					}
+					w.Write([]byte(tt.body[i:end]))
+				}
+			})
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			if tt.rangeHeader != "" {
+				req.Header.Set("Range", tt.rangeHeader)
+			}
+
+			Compress(CompressOptions{})(next).ServeHTTP(rr, req)
+
+			rs := rr.Result()
+			actual := rs.Header.Get("Content-Encoding")
+			if actual != tt.expectedEncoding {
+				t.Errorf("Expected %q got %q", tt.expectedEncoding, actual)
+			}
+			if rs.Header.Get("Vary") != "Accept-Encoding" {
+				t.Errorf("Expected %q got %q", "Accept-Encoding", rs.Header.Get("Vary"))
+			}
+
+			var body io.Reader = rs.Body
+			switch tt.expectedEncoding {
+			case "gzip":
+				gr, err := gzip.NewReader(rs.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = gr
+			case "deflate":
+				body = flate.NewReader(rs.Body)
+			}
+			b, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.body {
+				t.Errorf("Expected body of length %d got %d", len(tt.body), len(b))
+			}
+		})
+	}
+}
+
+func TestCompressStatusCode(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(strings.Repeat("a", 2048)))
+	})
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	Compress(CompressOptions{})(next).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Errorf("Expected %d got %d", http.StatusCreated, rr.Code)
+	}
+	if rr.Header().Get("Content-Encoding") != "gzip" {
+		t.Errorf("Expected %q got %q", "gzip", rr.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestCompressFlush(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: 1\n\n"))
+		w.(http.Flusher).Flush()
+	})
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	Compress(CompressOptions{})(next).ServeHTTP(rr, req)
+
+	if !rr.Flushed {
+		t.Error("Expected response to be flushed")
+	}
+	gr, err := gzip.NewReader(rr.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(gr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "data: 1\n\n" {
+		t.Errorf("Expected %q got %q", "data: 1\n\n", string(b))
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{"Empty", "", ""},
+		{"Gzip preferred", "deflate, gzip", "gzip"},
+		{"Deflate only", "deflate", "deflate"},
+		{"Refused gzip", "gzip;q=0, deflate", "deflate"},
+		{"Wildcard", "*", "gzip"},
+		{"Wildcard does not override q=0", "gzip;q=0, *", "deflate"},
+		{"Everything refused", "gzip;q=0, deflate;q=0, *", ""},
+		{"Wildcard refused", "*;q=0", ""},
+		{"Identity only", "identity", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := negotiateEncoding(tt.header); actual != tt.expected {
+				t.Errorf("Expected %q got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCompressPanic(t *testing.T) {
+	lh := &mockLogHolder{errorLog: log.New(ioutil.Discard, "", 0)}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("partial"))
+		panic("boom")
+	})
+	rr := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+
+	NewEme(RecoverPanic(lh), Compress(CompressOptions{})).Apply(next).ServeHTTP(rr, r)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected %d got %d", http.StatusInternalServerError, rr.Code)
+	}
+	if strings.Contains(rr.Body.String(), "partial") {
+		t.Errorf("Expected the partial body to be discarded got %q", rr.Body.String())
+	}
+}
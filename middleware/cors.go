@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"github.com/tomascaslo/godinez"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSOptions configures the CORS middleware.
+type CORSOptions struct {
+	// AllowedOrigins lists the origins allowed to make cross-origin requests.
+	// Entries may be patterns such as "https://*.example.com", and "*" allows
+	// any origin.
+	AllowedOrigins []string
+	// AllowOriginFunc is called for origins not matched by AllowedOrigins.
+	AllowOriginFunc func(origin string) bool
+	// AllowedMethods defaults to GET, HEAD and POST.
+	AllowedMethods []string
+	// AllowedHeaders defaults to Accept, Content-Type and X-CSRF-Token.
+	// "*" allows any header requested in a preflight.
+	AllowedHeaders []string
+	// ExposedHeaders lists the response headers readable by the client.
+	ExposedHeaders []string
+	// AllowCredentials lets clients send cookies, which also means the
+	// origin is echoed instead of answering with "*". It cannot be combined
+	// with the "*" origin, since any website could then make authenticated
+	// requests; use AllowOriginFunc to decide which origins to trust.
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight response.
+	MaxAge time.Duration
+}
+
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+	defaultCORSHeaders = []string{"Accept", "Content-Type", "X-CSRF-Token"}
+)
+
+// CORS sets the Cross-Origin Resource Sharing headers described by opts.
+// Preflight requests are answered here and never reach next, so CORS must be
+// applied before NoSurf, e.g. NewEme(CORS(opts), NoSurf), for preflights to
+// skip the CSRF check.
+//
+// CORS panics if AllowCredentials is combined with the "*" origin.
+func CORS(opts CORSOptions) Mw {
+	methods := opts.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	headers := opts.AllowedHeaders
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+	allowAnyOrigin := contains(opts.AllowedOrigins, "*")
+	allowAnyHeader := contains(headers, "*")
+	if allowAnyOrigin && opts.AllowCredentials {
+		panic(`middleware: CORS cannot allow credentials for the "*" origin, use AllowOriginFunc instead`)
+	}
+
+	allowOrigin := func(origin string) bool {
+		if allowAnyOrigin {
+			return true
+		}
+		for _, pattern := range opts.AllowedOrigins {
+			if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(origin)); ok {
+				return true
+			}
+		}
+		return opts.AllowOriginFunc != nil && opts.AllowOriginFunc(origin)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			w.Header().Add("Vary", "Origin")
+			if preflight {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+			}
+
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !allowOrigin(origin) {
+				if preflight {
+					godinez.ClientError(w, http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if allowAnyOrigin {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if opts.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if len(opts.ExposedHeaders) > 0 {
+					w.Header().Set("Access-Control-Expose-Headers", strings.Join(opts.ExposedHeaders, ", "))
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			method := r.Header.Get("Access-Control-Request-Method")
+			if !contains(methods, method) {
+				godinez.ClientError(w, http.StatusMethodNotAllowed)
+				return
+			}
+			requested := r.Header.Get("Access-Control-Request-Headers")
+			if !allowAnyHeader {
+				for _, h := range strings.Split(requested, ",") {
+					h = strings.TrimSpace(h)
+					if h != "" && !contains(headers, h) {
+						godinez.ClientError(w, http.StatusForbidden)
+						return
+					}
+				}
+			}
+
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+			if allowAnyHeader {
+				if requested != "" {
+					w.Header().Set("Access-Control-Allow-Headers", requested)
+				}
+			} else {
+				w.Header().Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+			}
+			if opts.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// contains reports whether values holds s, ignoring case since header
+// names are case-insensitive.
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
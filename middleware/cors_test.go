@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORS(t *testing.T) {
+	opts := CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		AllowedMethods:   []string{"GET", "POST", "DELETE"},
+		ExposedHeaders:   []string{"X-Total-Count"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+
+	tests := []struct {
+		name                  string
+		method                string
+		headers               map[string]string
+		expectedStatusCode    int
+		expectedAllowOrigin   string
+		expectedAllowMethods  string
+		expectedExposeHeaders string
+		expectedMaxAge        string
+		expectedNextCalled    bool
+	}{
+		{
+			"Same origin request",
+			"GET",
+			map[string]string{},
+			http.StatusOK, "", "", "", "", true,
+		},
+		{
+			"Allowed origin",
+			"GET",
+			map[string]string{"Origin": "https://app.example.com"},
+			http.StatusOK, "https://app.example.com", "", "X-Total-Count", "", true,
+		},
+		{
+			"Allowed origin pattern",
+			"GET",
+			map[string]string{"Origin": "https://api.example.org"},
+			http.StatusOK, "https://api.example.org", "", "X-Total-Count", "", true,
+		},
+		{
+			"Disallowed origin",
+			"GET",
+			map[string]string{"Origin": "https://evil.com"},
+			http.StatusOK, "", "", "", "", true,
+		},
+		{
+			"Preflight",
+			"OPTIONS",
+			map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  "DELETE",
+				"Access-Control-Request-Headers": "content-type, x-csrf-token",
+			},
+			http.StatusNoContent, "https://app.example.com", "GET, POST, DELETE", "", "600", false,
+		},
+		{
+			"Preflight disallowed method",
+			"OPTIONS",
+			map[string]string{
+				"Origin":                        "https://app.example.com",
+				"Access-Control-Request-Method": "PUT",
+			},
+			http.StatusMethodNotAllowed, "https://app.example.com", "", "", "", false,
+		},
+		{
+			"Preflight disallowed header",
+			"OPTIONS",
+			map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "X-Custom",
+			},
+			http.StatusForbidden, "https://app.example.com", "", "", "", false,
+		},
+		{
+			"Preflight disallowed origin",
+			"OPTIONS",
+			map[string]string{
+				"Origin":                        "https://evil.com",
+				"Access-Control-Request-Method": "POST",
+			},
+			http.StatusForbidden, "", "", "", "", false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nextCalled := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+			})
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, "/api", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			CORS(opts)(next).ServeHTTP(rr, req)
+
+			rs := rr.Result()
+			if rs.StatusCode != tt.expectedStatusCode {
+				t.Errorf("Expected %d got %d", tt.expectedStatusCode, rs.StatusCode)
+			}
+			if nextCalled != tt.expectedNextCalled {
+				t.Errorf("Expected %t got %t", tt.expectedNextCalled, nextCalled)
+			}
+
+			expectedHeaders := map[string]string{
+				"Access-Control-Allow-Origin":   tt.expectedAllowOrigin,
+				"Access-Control-Allow-Methods":  tt.expectedAllowMethods,
+				"Access-Control-Expose-Headers": tt.expectedExposeHeaders,
+				"Access-Control-Max-Age":        tt.expectedMaxAge,
+			}
+			for name, expected := range expectedHeaders {
+				if actual := rs.Header.Get(name); actual != expected {
+					t.Errorf("Expected %s %q got %q", name, expected, actual)
+				}
+			}
+
+			if rs.Header.Get("Vary") != "Origin" {
+				t.Errorf("Expected %q got %q", "Origin", rs.Header.Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestCORSAnyOrigin(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Origin", "https://anywhere.com")
+
+	CORS(CORSOptions{AllowedOrigins: []string{"*"}})(next).ServeHTTP(rr, req)
+
+	actual := rr.Result().Header.Get("Access-Control-Allow-Origin")
+	if actual != "*" {
+		t.Errorf("Expected %q got %q", "*", actual)
+	}
+}
+
+func TestCORSSkipsNoSurfOnPreflight(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := NewEme(CORS(CORSOptions{AllowedOrigins: []string{"https://app.example.com"}}), NoSurf).Apply(next)
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("OPTIONS", "/", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+
+	h.ServeHTTP(rr, req)
+
+	rs := rr.Result()
+	if rs.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected %d got %d", http.StatusNoContent, rs.StatusCode)
+	}
+	if getCookie(rs.Cookies(), "csrf_token") != nil {
+		t.Error("Expected csrf_token cookie not to be set on preflight")
+	}
+}
+
+func TestCORSAnyOriginWithCredentials(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic")
+		}
+	}()
+
+	CORS(CORSOptions{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+}
+
+func TestCORSAllowOriginFuncWithCredentials(t *testing.T) {
+	opts := CORSOptions{
+		AllowOriginFunc:  func(origin string) bool { return origin == "https://trusted.example.com" },
+		AllowCredentials: true,
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	tests := []struct {
+		name                string
+		origin              string
+		expectedAllowOrigin string
+	}{
+		{"Trusted origin", "https://trusted.example.com", "https://trusted.example.com"},
+		{"Other origin", "https://evil.example.com", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Origin", tt.origin)
+
+			CORS(opts)(next).ServeHTTP(rr, r)
+
+			if actual := rr.Header().Get("Access-Control-Allow-Origin"); actual != tt.expectedAllowOrigin {
+				t.Errorf("Expected %q got %q", tt.expectedAllowOrigin, actual)
+			}
+		})
+	}
+}
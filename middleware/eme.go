@@ -18,6 +18,12 @@ func NewEme(mws ...Mw) *Eme {
 	return &Eme{append([]Mw{}, mws...)}
 }
 
+// With returns a new *Eme running the middleware of e followed by mws, e.g.
+// a route specific timeout: DefaultEme(app).With(Timeout(30 * time.Second)).
+func (e *Eme) With(mws ...Mw) *Eme {
+	return NewEme(append(append([]Mw{}, e.mws...), mws...)...)
+}
+
 // ApplyFunc runs middleware with a function and returns the http.HandlerFunc
 // by calling do().
 func (e *Eme) ApplyFunc(f func(w http.ResponseWriter, r *http.Request)) http.Handler {
@@ -1,9 +1,20 @@
 package middleware
 
-import "errors"
+import (
+	"errors"
+	"github.com/tomascaslo/godinez/forms"
+)
 
 var (
 	ErrNoRecord           = errors.New("models: no matching record found")
 	ErrInvalidCredentials = errors.New("models: invalid credentials")
 	ErrDuplicateEmail     = errors.New("models: duplicate email")
 )
+
+// Messages used by forms.Form.AddError, e.g. form.AddError("email", err)
+// when inserting a user fails with ErrDuplicateEmail. The messages live in
+// the forms catalogs, only the errors are registered here.
+func init() {
+	forms.RegisterErrorMessage(ErrDuplicateEmail, forms.MsgDuplicateEmail)
+	forms.RegisterErrorMessage(ErrInvalidCredentials, forms.MsgInvalidCredentials)
+}
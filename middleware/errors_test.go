@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/tomascaslo/godinez/forms"
+	"net/url"
+	"testing"
+)
+
+func TestErrorMessages(t *testing.T) {
+	form := forms.New(url.Values{})
+	form.AddError("email", ErrDuplicateEmail)
+
+	actual := form.Errors.Get("email")
+	expected := "Address is already in use"
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// HTTPSOptions configures the RedirectHTTPS middleware.
+type HTTPSOptions struct {
+	// Port is the HTTPS port, empty for 443.
+	Port string
+	// TrustedProxies lists the IPs or CIDRs, e.g. "10.0.0.0/8", of the
+	// proxies terminating TLS. Their X-Forwarded-Proto header is used to
+	// tell HTTPS requests apart, it is ignored for any other client.
+	TrustedProxies []string
+}
+
+// RedirectHTTPS redirects requests not made over TLS to HTTPS, keeping the
+// method with a 308 status. Requests without a Host can't be redirected
+// and get a 400. Use it as the handler of the plain HTTP server:
+//
+//	go http.ListenAndServe(":80", RedirectHTTPS(HTTPSOptions{})(http.NotFoundHandler()))
+//
+// or in a chain when the same handler serves both. Behind a proxy that
+// terminates TLS list it in TrustedProxies, otherwise every request is
+// redirected. RedirectHTTPS panics if a trusted proxy is not valid.
+func RedirectHTTPS(opts HTTPSOptions) Mw {
+	proxies := make([]*net.IPNet, 0, len(opts.TrustedProxies))
+	for _, proxy := range opts.TrustedProxies {
+		if !strings.Contains(proxy, "/") {
+			if strings.Contains(proxy, ":") {
+				proxy += "/128"
+			} else {
+				proxy += "/32"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			panic("middleware: invalid trusted proxy " + proxy)
+		}
+		proxies = append(proxies, ipNet)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS != nil || (trustedProxy(proxies, r) && strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			host := r.Host
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				host = h
+			} else {
+				host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+			}
+			if host == "" {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+			if opts.Port != "" && opts.Port != "443" {
+				host = net.JoinHostPort(host, opts.Port)
+			} else if strings.Contains(host, ":") {
+				// IPv6 addresses keep their brackets without a port
+				host = "[" + host + "]"
+			}
+			http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+		})
+	}
+}
+
+// trustedProxy reports whether the request comes from one of proxies.
+func trustedProxy(proxies []*net.IPNet, r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range proxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
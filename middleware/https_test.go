@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirectHTTPS(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Hello"))
+	})
+	tests := []struct {
+		name             string
+		port             string
+		target           string
+		tls              bool
+		remoteAddr       string
+		forwardedProto   string
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{"Default port", "", "http://example.com:8080/users?page=2", false, "", "", http.StatusPermanentRedirect, "https://example.com/users?page=2"},
+		{"Custom port", "4000", "http://localhost:8080/", false, "", "", http.StatusPermanentRedirect, "https://localhost:4000/"},
+		{"IPv6 host", "4000", "http://[::1]:8080/", false, "", "", http.StatusPermanentRedirect, "https://[::1]:4000/"},
+		{"IPv6 host default port", "", "http://[::1]:8080/a", false, "", "", http.StatusPermanentRedirect, "https://[::1]/a"},
+		{"IPv6 host without port", "", "http://[::1]/a", false, "", "", http.StatusPermanentRedirect, "https://[::1]/a"},
+		{"Already HTTPS", "", "https://example.com/", true, "", "", http.StatusOK, ""},
+		{"HTTPS behind trusted proxy", "", "http://example.com/", false, "10.0.0.5:4321", "https", http.StatusOK, ""},
+		{"HTTP behind trusted proxy", "", "http://example.com/", false, "10.0.0.5:4321", "http", http.StatusPermanentRedirect, "https://example.com/"},
+		{"Forwarded by untrusted client", "", "http://example.com/", false, "203.0.113.7:4321", "https", http.StatusPermanentRedirect, "https://example.com/"},
+		{"Empty host", "", "http://example.com/", false, "", "", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", tt.target, nil)
+			if tt.remoteAddr != "" {
+				r.RemoteAddr = tt.remoteAddr
+			}
+			if tt.forwardedProto != "" {
+				r.Header.Set("X-Forwarded-Proto", tt.forwardedProto)
+			}
+			if tt.expectedStatus == http.StatusBadRequest {
+				r.Host = ""
+			}
+			if !tt.tls {
+				r.TLS = nil
+			} else if r.TLS == nil {
+				r.TLS = &tls.ConnectionState{}
+			}
+
+			opts := HTTPSOptions{Port: tt.port, TrustedProxies: []string{"10.0.0.0/8", "::1"}}
+			RedirectHTTPS(opts)(next).ServeHTTP(rr, r)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected %d got %d", tt.expectedStatus, rr.Code)
+			}
+			if location := rr.Header().Get("Location"); location != tt.expectedLocation {
+				t.Errorf("Expected %q got %q", tt.expectedLocation, location)
+			}
+		})
+	}
+}
+
+func TestRedirectHTTPSInvalidProxy(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic")
+		}
+	}()
+
+	RedirectHTTPS(HTTPSOptions{TrustedProxies: []string{"not-an-ip"}})
+}
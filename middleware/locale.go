@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"github.com/tomascaslo/godinez/i18n"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// LocaleOptions configures the Locale middleware.
+type LocaleOptions struct {
+	// URLPrefix enables locales as the first path segment, e.g. /es/about.
+	// The prefix is removed before calling the next handler.
+	URLPrefix bool
+	// CookieName is the cookie holding the locale chosen by the user.
+	// Leave it empty to ignore cookies.
+	CookieName string
+}
+
+// Locale negotiates the locale of each request among the locales of b and
+// stores it in the request context, see i18n.LocaleFromContext. The URL
+// prefix wins over the cookie, which wins over the Accept-Language header,
+// and b.DefaultLocale is used when none of them match.
+func Locale(b *i18n.Bundle, opts LocaleOptions) Mw {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			locale := ""
+
+			if opts.URLPrefix {
+				segments := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+				if segments[0] != "" && b.Supports(segments[0]) {
+					locale = segments[0]
+					u := new(url.URL)
+					*u = *r.URL
+					u.Path = "/"
+					if len(segments) == 2 {
+						u.Path += segments[1]
+					}
+					u.RawPath = ""
+					r2 := new(http.Request)
+					*r2 = *r
+					r2.URL = u
+					r = r2
+				}
+			}
+
+			if locale == "" && opts.CookieName != "" {
+				if c, err := r.Cookie(opts.CookieName); err == nil && b.Supports(c.Value) {
+					locale = c.Value
+				}
+			}
+
+			if locale == "" {
+				locale = i18n.Negotiate(r.Header.Get("Accept-Language"), b.Locales())
+			}
+
+			if locale == "" {
+				locale = b.DefaultLocale
+			}
+
+			w.Header().Add("Vary", "Accept-Language")
+			next.ServeHTTP(w, r.WithContext(i18n.WithLocale(r.Context(), strings.ToLower(locale))))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"github.com/tomascaslo/godinez/i18n"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLocale(t *testing.T) {
+	b := i18n.NewBundle("en")
+	opts := LocaleOptions{URLPrefix: true, CookieName: "lang"}
+
+	tests := []struct {
+		name           string
+		url            string
+		cookie         string
+		acceptLanguage string
+		expectedLocale string
+		expectedPath   string
+	}{
+		{"Default locale", "/about", "", "", "en", "/about"},
+		{"Accept-Language", "/about", "", "es-MX,es;q=0.9", "es", "/about"},
+		{"Cookie wins over header", "/about", "en", "es", "en", "/about"},
+		{"URL prefix wins over cookie", "/es/about", "en", "en", "es", "/about"},
+		{"URL prefix root", "/es", "", "", "es", "/"},
+		{"Unsupported prefix", "/fr/about", "", "", "en", "/fr/about"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actualLocale, actualPath string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actualLocale = i18n.LocaleFromContext(r.Context())
+				actualPath = r.URL.Path
+			})
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tt.url, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "lang", Value: tt.cookie})
+			}
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			Locale(b, opts)(next).ServeHTTP(rr, req)
+
+			if actualLocale != tt.expectedLocale {
+				t.Errorf("Expected %q got %q", tt.expectedLocale, actualLocale)
+			}
+			if actualPath != tt.expectedPath {
+				t.Errorf("Expected %q got %q", tt.expectedPath, actualPath)
+			}
+		})
+	}
+}
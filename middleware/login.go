@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/tomascaslo/godinez/forms"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type loginAttempts struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// LoginThrottle tracks failed credential checks per account and per client IP.
+// After MaxAttempts failures of an account, or MaxIPAttempts failures from an
+// IP, the key is locked for BaseDelay, and the lockout doubles with every
+// further failure up to MaxDelay. Failures are forgotten once a key has been
+// quiet for ResetAfter.
+//
+// The IP threshold is higher since many users may share an IP behind a NAT,
+// and it is not cleared by a successful login so that an attacker cannot
+// reset it with an account of their own.
+//
+// The IP is read by IPKey, KeyByIP by default. Behind a reverse proxy every
+// client has the proxy's address, so failures from anyone would lock out
+// every user: set IPKey to a KeyFunc reading the address forwarded by the
+// trusted proxy, or to one returning "" to only throttle accounts.
+type LoginThrottle struct {
+	MaxAttempts   int
+	MaxIPAttempts int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	ResetAfter    time.Duration
+	IPKey         KeyFunc
+
+	mu        sync.Mutex
+	attempts  map[string]*loginAttempts
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewLoginThrottle initializes a *LoginThrottle that starts locking accounts
+// after maxAttempts failures and IPs after ten times as many. Lockouts start
+// at one minute and are capped at one hour.
+func NewLoginThrottle(maxAttempts int) *LoginThrottle {
+	return &LoginThrottle{
+		MaxAttempts:   maxAttempts,
+		MaxIPAttempts: 10 * maxAttempts,
+		BaseDelay:     time.Minute,
+		MaxDelay:      time.Hour,
+		ResetAfter:    time.Hour,
+		IPKey:         KeyByIP,
+		attempts:      map[string]*loginAttempts{},
+		now:           time.Now,
+	}
+}
+
+// loginKeys returns the account key followed by the IP key of r, if any.
+func (lt *LoginThrottle) loginKeys(account string, r *http.Request) []string {
+	keys := []string{"account:" + strings.ToLower(strings.TrimSpace(account))}
+	if ip := lt.IPKey(r); ip != "" {
+		keys = append(keys, "ip:"+ip)
+	}
+	return keys
+}
+
+// Wait returns how long the account and the client of r must wait
+// before trying to log in again. Zero means they can try now.
+func (lt *LoginThrottle) Wait(account string, r *http.Request) time.Duration {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	now := lt.now()
+	lt.sweep(now)
+
+	var wait time.Duration
+	for _, key := range lt.loginKeys(account, r) {
+		if a, ok := lt.attempts[key]; ok && a.lockedUntil.After(now) {
+			if d := a.lockedUntil.Sub(now); d > wait {
+				wait = d
+			}
+		}
+	}
+	return wait
+}
+
+// Record updates the throttle with the result of a credential check.
+// A middleware.ErrInvalidCredentials error counts as a failure, a nil
+// error clears the failures of the account and any other error is ignored.
+func (lt *LoginThrottle) Record(err error, account string, r *http.Request) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	keys := lt.loginKeys(account, r)
+	if err == nil {
+		delete(lt.attempts, keys[0])
+		return
+	}
+	if !errors.Is(err, ErrInvalidCredentials) {
+		return
+	}
+
+	now := lt.now()
+	for i, key := range keys {
+		maxAttempts := lt.MaxAttempts
+		if i == 1 {
+			maxAttempts = lt.MaxIPAttempts
+		}
+		a, ok := lt.attempts[key]
+		if !ok || now.Sub(a.lastFailure) >= lt.ResetAfter {
+			a = &loginAttempts{}
+			lt.attempts[key] = a
+		}
+		a.failures++
+		a.lastFailure = now
+		if a.failures >= maxAttempts {
+			a.lockedUntil = now.Add(lt.delay(a.failures, maxAttempts))
+		}
+	}
+}
+
+// Allow reports whether the account and the client of r can attempt to log in.
+// When they cannot, a "too many attempts" message is added to field in form
+// so it can be rendered like any other validation error.
+func (lt *LoginThrottle) Allow(form *forms.Form, field, account string, r *http.Request) bool {
+	wait := lt.Wait(account, r)
+	if wait <= 0 {
+		return true
+	}
+
+	minutes := int(math.Ceil(wait.Minutes()))
+	if minutes == 1 {
+		form.Errors.Add(field, form.Message(forms.MsgTooManyAttemptsShort))
+	} else {
+		form.Errors.Add(field, form.Message(forms.MsgTooManyAttempts, minutes))
+	}
+	return false
+}
+
+// delay returns the lockout for the given number of failures, doubling
+// BaseDelay for every failure past maxAttempts.
+func (lt *LoginThrottle) delay(failures, maxAttempts int) time.Duration {
+	d := lt.BaseDelay
+	for i := maxAttempts; i < failures && d < lt.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > lt.MaxDelay {
+		return lt.MaxDelay
+	}
+	return d
+}
+
+func (lt *LoginThrottle) sweep(now time.Time) {
+	if now.Sub(lt.lastSweep) < lt.ResetAfter {
+		return
+	}
+	for key, a := range lt.attempts {
+		if now.Sub(a.lastFailure) >= lt.ResetAfter && !a.lockedUntil.After(now) {
+			delete(lt.attempts, key)
+		}
+	}
+	lt.lastSweep = now
+}
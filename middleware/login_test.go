@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"github.com/tomascaslo/godinez/forms"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestLoginThrottle(t *testing.T) {
+	now := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	lt := NewLoginThrottle(3)
+	lt.now = func() time.Time { return now }
+	req := httptest.NewRequest("POST", "/user/login", nil)
+	req.RemoteAddr = "1.1.1.1:1234"
+
+	for i := 0; i < 2; i++ {
+		lt.Record(ErrInvalidCredentials, "john@test.com", req)
+	}
+	if wait := lt.Wait("john@test.com", req); wait != 0 {
+		t.Errorf("Expected %v got %v", time.Duration(0), wait)
+	}
+
+	lt.Record(ErrInvalidCredentials, "John@test.com", req)
+	if wait := lt.Wait("john@test.com", req); wait != time.Minute {
+		t.Errorf("Expected %v got %v", time.Minute, wait)
+	}
+
+	lt.Record(ErrInvalidCredentials, "john@test.com", req)
+	if wait := lt.Wait("john@test.com", req); wait != 2*time.Minute {
+		t.Errorf("Expected %v got %v", 2*time.Minute, wait)
+	}
+
+	// Other errors are not credential failures
+	lt.Record(errors.New("db down"), "john@test.com", req)
+	if wait := lt.Wait("john@test.com", req); wait != 2*time.Minute {
+		t.Errorf("Expected %v got %v", 2*time.Minute, wait)
+	}
+
+	// The IP has its own, higher, threshold
+	if wait := lt.Wait("jane@test.com", req); wait != 0 {
+		t.Errorf("Expected %v got %v", time.Duration(0), wait)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if wait := lt.Wait("john@test.com", req); wait != 0 {
+		t.Errorf("Expected %v got %v", time.Duration(0), wait)
+	}
+}
+
+func TestLoginThrottleDelay(t *testing.T) {
+	lt := NewLoginThrottle(3)
+	lt.MaxDelay = 5 * time.Minute
+
+	tests := []struct {
+		failures int
+		expected time.Duration
+	}{
+		{3, time.Minute},
+		{4, 2 * time.Minute},
+		{5, 4 * time.Minute},
+		{6, 5 * time.Minute},
+		{20, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		actual := lt.delay(tt.failures, lt.MaxAttempts)
+		if actual != tt.expected {
+			t.Errorf("Expected %v got %v", tt.expected, actual)
+		}
+	}
+}
+
+func TestLoginThrottleAllow(t *testing.T) {
+	now := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	lt := NewLoginThrottle(1)
+	lt.MaxIPAttempts = 2
+	lt.now = func() time.Time { return now }
+	req := httptest.NewRequest("POST", "/user/login", nil)
+
+	form := forms.New(url.Values{})
+	if !lt.Allow(form, "generic", "john@test.com", req) {
+		t.Error("Expected login attempt to be allowed")
+	}
+
+	lt.Record(ErrInvalidCredentials, "john@test.com", req)
+	lt.Record(ErrInvalidCredentials, "john@test.com", req)
+	now = now.Add(30 * time.Second)
+	if lt.Allow(form, "generic", "john@test.com", req) {
+		t.Error("Expected login attempt to be throttled")
+	}
+
+	actual := form.Errors.Get("generic")
+	expected := "Too many attempts, try again in 2 minutes"
+	if actual != expected {
+		t.Errorf("Expected %q got %q", expected, actual)
+	}
+
+	lt.Record(nil, "john@test.com", req)
+	if lt.Wait("john@test.com", httptest.NewRequest("POST", "/user/login", nil)) == 0 {
+		t.Error("Expected IP to remain locked after a successful login")
+	}
+}
+
+func TestLoginThrottleSharedIP(t *testing.T) {
+	now := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	lt := NewLoginThrottle(3)
+	lt.now = func() time.Time { return now }
+	req := httptest.NewRequest("POST", "/user/login", nil)
+	req.RemoteAddr = "1.1.1.1:1234"
+
+	// A few typos from many users behind the same NAT
+	for i := 0; i < 29; i++ {
+		lt.Record(ErrInvalidCredentials, fmt.Sprintf("user%d@test.com", i%10), req)
+	}
+	if wait := lt.Wait("jane@test.com", req); wait != 0 {
+		t.Errorf("Expected %v got %v", time.Duration(0), wait)
+	}
+
+	lt.Record(ErrInvalidCredentials, "user9@test.com", req)
+	if wait := lt.Wait("jane@test.com", req); wait != time.Minute {
+		t.Errorf("Expected %v got %v", time.Minute, wait)
+	}
+}
+
+func TestLoginThrottleIPKey(t *testing.T) {
+	now := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	lt := NewLoginThrottle(1)
+	lt.now = func() time.Time { return now }
+	lt.IPKey = func(r *http.Request) string { return r.Header.Get("X-Real-IP") }
+	proxied := func(ip string) *http.Request {
+		req := httptest.NewRequest("POST", "/user/login", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Real-IP", ip)
+		return req
+	}
+
+	for i := 0; i < 10; i++ {
+		lt.Record(ErrInvalidCredentials, fmt.Sprintf("user%d@test.com", i), proxied("1.1.1.1"))
+	}
+	if wait := lt.Wait("jane@test.com", proxied("1.1.1.1")); wait != time.Minute {
+		t.Errorf("Expected %v got %v", time.Minute, wait)
+	}
+	if wait := lt.Wait("jane@test.com", proxied("2.2.2.2")); wait != 0 {
+		t.Errorf("Expected %v got %v", time.Duration(0), wait)
+	}
+	if wait := lt.Wait("jane@test.com", proxied("")); wait != 0 {
+		t.Errorf("Expected %v got %v", time.Duration(0), wait)
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"github.com/tomascaslo/godinez/metrics"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var panics = metrics.Default.NewCounter("godinez_panics_total", "Panics recovered by RecoverPanic.")
+
+// Metrics records the number, latency and status of requests in reg, or
+// metrics.Default if nil, along with the requests in flight. Serve them
+// with mux.Handle("/metrics", reg.Handler()).
+//
+// Requests are labeled with the route pattern set by http.ServeMux, so
+// Metrics must either wrap the mux without middleware copying the request
+// in between, or be applied per route with Eme. Requests without a
+// pattern are labeled "unmatched" and non-standard methods "OTHER" to keep
+// the number of series bounded. Panics are recorded as 500, the response
+// RecoverPanic writes, unless the response was already started.
+func Metrics(reg *metrics.Registry) Mw {
+	if reg == nil {
+		reg = metrics.Default
+	}
+	requests := reg.NewCounter("http_requests_total", "HTTP requests served.", "method", "route", "status")
+	duration := reg.NewHistogram("http_request_duration_seconds", "HTTP request latency in seconds.", nil, "method", "route", "status")
+	inFlight := reg.NewGauge("http_requests_in_flight", "HTTP requests being served.", "method")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			method := metricMethod(r.Method)
+			inFlight.Inc(method)
+			sw := newStatusWriter(w)
+			defer func() {
+				rec := recover()
+				inFlight.Dec(method)
+				route := r.Pattern
+				if route == "" {
+					route = "unmatched"
+				}
+				status := strconv.Itoa(panicStatus(sw, rec))
+				requests.Inc(method, route, status)
+				duration.Observe(time.Since(start).Seconds(), method, route, status)
+				if rec != nil {
+					panic(rec)
+				}
+			}()
+
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
+
+// metricMethod returns the method label of m, "OTHER" unless m is one of
+// the standard methods.
+func metricMethod(m string) string {
+	switch m {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return m
+	}
+	return "OTHER"
+}
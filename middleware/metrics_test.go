@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"bytes"
+	"github.com/tomascaslo/godinez/metrics"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /posts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("post"))
+	})
+	mux.Handle("POST /posts", NewEme(Metrics(reg)).ApplyFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	wrapped := Metrics(reg)(mux)
+
+	for _, r := range []*http.Request{
+		httptest.NewRequest("GET", "/posts/1", nil),
+		httptest.NewRequest("GET", "/posts/2", nil),
+		httptest.NewRequest("GET", "/missing", nil),
+		httptest.NewRequest("FOOBAR", "/missing", nil),
+		httptest.NewRequest("BAZ", "/missing", nil),
+	} {
+		wrapped.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/posts", nil))
+
+	buf := new(bytes.Buffer)
+	reg.WriteTo(buf)
+	for _, expected := range []string{
+		`http_requests_total{method="GET",route="GET /posts/{id}",status="200"} 2`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`http_requests_total{method="POST",route="POST /posts",status="201"} 1`,
+		`http_request_duration_seconds_count{method="GET",route="GET /posts/{id}",status="200"} 2`,
+		`http_requests_in_flight{method="GET"} 0`,
+		`http_requests_total{method="OTHER",route="unmatched",status="404"} 2`,
+		`http_request_duration_seconds_count{method="OTHER",route="unmatched",status="404"} 2`,
+		`http_requests_in_flight{method="OTHER"} 0`,
+	} {
+		if !strings.Contains(buf.String(), expected+"\n") {
+			t.Errorf("Expected %q in %q", expected, buf.String())
+		}
+	}
+	for _, unexpected := range []string{"FOOBAR", "BAZ"} {
+		if strings.Contains(buf.String(), unexpected) {
+			t.Errorf("Expected no %q in %q", unexpected, buf.String())
+		}
+	}
+}
+
+func TestRecoverPanicMetrics(t *testing.T) {
+	before := panicCount()
+	lh := &mockLogHolder{errorLog: log.New(ioutil.Discard, "", 0)}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	RecoverPanic(lh)(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if actual := panicCount(); actual != before+1 {
+		t.Errorf("Expected %d got %d", before+1, actual)
+	}
+}
+
+func TestMetricsPanic(t *testing.T) {
+	reg := metrics.NewRegistry()
+	lh := &mockLogHolder{errorLog: log.New(ioutil.Discard, "", 0)}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	rr := httptest.NewRecorder()
+
+	NewEme(RecoverPanic(lh), Metrics(reg)).Apply(next).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected %d got %d", http.StatusInternalServerError, rr.Code)
+	}
+	buf := new(bytes.Buffer)
+	reg.WriteTo(buf)
+	expected := `http_requests_total{method="GET",route="unmatched",status="500"} 1`
+	if !strings.Contains(buf.String(), expected+"\n") {
+		t.Errorf("Expected %q in %q", expected, buf.String())
+	}
+}
+
+func panicCount() int {
+	buf := new(bytes.Buffer)
+	metrics.Default.WriteTo(buf)
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasPrefix(line, "godinez_panics_total ") {
+			n, _ := strconv.Atoi(strings.TrimPrefix(line, "godinez_panics_total "))
+			return n
+		}
+	}
+	return 0
+}
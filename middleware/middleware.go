@@ -27,9 +27,19 @@ func SecureHeaders(next http.Handler) http.Handler {
 	})
 }
 
+// QuietPaths are not logged by LogRequest, so that frequent health checks
+// and metrics scrapes do not flood the logs.
+var QuietPaths = []string{"/healthz", "/readyz", "/metrics"}
+
 func LogRequest(lh logHolder) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, p := range QuietPaths {
+				if r.URL.Path == p {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
 			lh.GetInfoLogger().Printf("%s - %s %s %s", r.RemoteAddr, r.Proto, r.Method, r.URL.RequestURI())
 
 			next.ServeHTTP(w, r)
@@ -38,20 +48,9 @@ func LogRequest(lh logHolder) func(next http.Handler) http.Handler {
 	}
 }
 
+// RecoverPanic recovers panics of the next handlers, see RecoverPanicWith.
 func RecoverPanic(lh logHolder) func(next http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		fn := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			defer func() {
-				if err := recover(); err != nil {
-					w.Header().Set("Connection", "close")
-					godinez.ServerError(lh.GetErrorLogger(), w, fmt.Errorf("%s", err))
-				}
-			}()
-			next.ServeHTTP(w, r)
-		})
-
-		return fn
-	}
+	return RecoverPanicWith(lh, RecoverOptions{})
 }
 
 func RequireAuthentication(app applicationAuthenticator) func(next http.Handler) http.Handler {
@@ -77,3 +76,19 @@ func NoSurf(next http.Handler) http.Handler {
 	})
 	return csrfHandler
 }
+
+// DefaultEme returns the chain most apps start with: panic recovery,
+// request logging and secure headers, followed by the session and CSRF
+// protection when app has a session.
+func DefaultEme(app *godinez.App) *Eme {
+	mws := []Mw{RecoverPanic(app), LogRequest(app), SecureHeaders}
+	if session := app.GetSession(); session != nil {
+		mws = append(mws, session.Enable, NoSurf)
+	}
+	return NewEme(mws...)
+}
+
+// AuthenticatedEme returns DefaultEme followed by RequireAuthentication.
+func AuthenticatedEme(app *godinez.App) *Eme {
+	return DefaultEme(app).With(RequireAuthentication(app))
+}
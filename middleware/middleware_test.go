@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"github.com/tomascaslo/godinez"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -95,6 +96,16 @@ func TestLogRequest(t *testing.T) {
 	if actual != expected {
 		t.Errorf("Expected %q got %q", expected, actual)
 	}
+
+	t.Run("Quiet paths", func(t *testing.T) {
+		logBuf.Reset()
+
+		logRequestHandler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+
+		if logBuf.Len() != 0 {
+			t.Errorf("Expected no log got %q", logBuf.String())
+		}
+	})
 }
 
 func TestRecoverPanic(t *testing.T) {
@@ -219,3 +230,40 @@ func getCookie(cookies []*http.Cookie, name string) *http.Cookie {
 	}
 	return nil
 }
+
+func TestDefaultEme(t *testing.T) {
+	logBuf := new(bytes.Buffer)
+	app := godinez.NewApp(godinez.WithInfoLogger(log.New(logBuf, "", 0)), godinez.WithRedirectTo("/login"))
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Hello"))
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		DefaultEme(app).Apply(next).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+		if rr.Body.String() != "Hello" {
+			t.Errorf("Expected %q got %q", "Hello", rr.Body.String())
+		}
+		if rr.Header().Get("X-Frame-Options") != "deny" {
+			t.Errorf("Expected %q got %q", "deny", rr.Header().Get("X-Frame-Options"))
+		}
+		if logBuf.Len() == 0 {
+			t.Errorf("Expected the request to be logged")
+		}
+	})
+
+	t.Run("Authenticated", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		AuthenticatedEme(app).Apply(next).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+		if rr.Code != http.StatusFound {
+			t.Errorf("Expected %d got %d", http.StatusFound, rr.Code)
+		}
+		if rr.Header().Get("Location") != "/login" {
+			t.Errorf("Expected %q got %q", "/login", rr.Header().Get("Location"))
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/tomascaslo/godinez"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// KeyFunc returns the key a request is rate limited by.
+// Requests that return an empty key are not limited.
+type KeyFunc func(*http.Request) string
+
+// KeyByIP limits requests by the client IP address.
+func KeyByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// KeyByCookie limits requests by the value of the named cookie,
+// e.g. the session cookie. Requests without the cookie fall back to
+// the client IP address.
+func KeyByCookie(name string) KeyFunc {
+	return func(r *http.Request) string {
+		c, err := r.Cookie(name)
+		if err != nil || c.Value == "" {
+			return KeyByIP(r)
+		}
+		return "cookie:" + c.Value
+	}
+}
+
+// KeyByUser limits requests by the user returned from user,
+// usually read from the session. Anonymous requests, for which user
+// returns an empty string, fall back to the client IP address.
+func KeyByUser(user func(*http.Request) string) KeyFunc {
+	return func(r *http.Request) string {
+		if id := user(r); id != "" {
+			return "user:" + id
+		}
+		return KeyByIP(r)
+	}
+}
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// RateLimiter is a token bucket limiter that keeps one bucket per key in memory.
+// Each bucket holds up to Burst tokens and is refilled with Limit tokens
+// every Period. Buckets idle for longer than IdleTimeout are evicted.
+type RateLimiter struct {
+	Limit       int
+	Burst       int
+	Period      time.Duration
+	IdleTimeout time.Duration
+	Key         KeyFunc
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewRateLimiter initializes a *RateLimiter allowing limit requests per period
+// for every key returned by key. Burst defaults to limit and IdleTimeout to
+// ten times period. It panics if limit or period are not positive.
+func NewRateLimiter(limit int, period time.Duration, key KeyFunc) *RateLimiter {
+	if limit <= 0 || period <= 0 {
+		panic(fmt.Sprintf("middleware: NewRateLimiter needs a positive limit and period, got %d and %s", limit, period))
+	}
+	return &RateLimiter{
+		Limit:       limit,
+		Burst:       limit,
+		Period:      period,
+		IdleTimeout: 10 * period,
+		Key:         key,
+		buckets:     map[string]*bucket{},
+		now:         time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of key. It returns whether the request
+// is allowed, the tokens left and the time until a token is available again.
+func (rl *RateLimiter) Allow(key string) (bool, int, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.sweep(now)
+
+	rate := float64(rl.Limit) / rl.Period.Seconds()
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rl.Burst)}
+		rl.buckets[key] = b
+	} else {
+		b.tokens = math.Min(float64(rl.Burst), b.tokens+now.Sub(b.lastSeen).Seconds()*rate)
+	}
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+		return false, 0, wait
+	}
+	b.tokens--
+
+	reset := time.Duration((float64(rl.Burst) - b.tokens) / rate * float64(time.Second))
+	return true, int(b.tokens), reset
+}
+
+// sweep evicts idle buckets at most once every IdleTimeout so that the
+// store does not grow with every client ever seen.
+func (rl *RateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < rl.IdleTimeout {
+		return
+	}
+	for key, b := range rl.buckets {
+		if now.Sub(b.lastSeen) >= rl.IdleTimeout {
+			delete(rl.buckets, key)
+		}
+	}
+	rl.lastSweep = now
+}
+
+// RateLimit limits requests with rl, responding with 429 Too Many Requests
+// once a key runs out of tokens. Use a different *RateLimiter for each Eme to
+// set per route limits, e.g. a stricter one for the login form.
+func RateLimit(rl *RateLimiter) Mw {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := rl.Key(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ok, remaining, reset := rl.Allow(key)
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(rl.Burst))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(reset)))
+			if !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(seconds(reset)))
+				godinez.ClientError(w, http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// seconds rounds d up to whole seconds as expected by Retry-After.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	now := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	rl := NewRateLimiter(2, time.Minute, KeyByIP)
+	rl.now = func() time.Time { return now }
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	})
+	h := NewEme(RateLimit(rl)).Apply(next)
+
+	tests := []struct {
+		name               string
+		remoteAddr         string
+		advance            time.Duration
+		expectedStatusCode int
+		expectedRemaining  string
+		expectedRetryAfter string
+	}{
+		{"First request", "1.1.1.1:1234", 0, http.StatusOK, "1", ""},
+		{"Second request", "1.1.1.1:1234", 0, http.StatusOK, "0", ""},
+		{"Limit exceeded", "1.1.1.1:4321", 0, http.StatusTooManyRequests, "0", "30"},
+		{"Other client", "2.2.2.2:1234", 0, http.StatusOK, "1", ""},
+		{"Token refilled", "1.1.1.1:1234", 30 * time.Second, http.StatusOK, "0", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.advance)
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/login", nil)
+			req.RemoteAddr = tt.remoteAddr
+
+			h.ServeHTTP(rr, req)
+
+			rs := rr.Result()
+			if rs.StatusCode != tt.expectedStatusCode {
+				t.Errorf("Expected %d got %d", tt.expectedStatusCode, rs.StatusCode)
+			}
+
+			actual := rs.Header.Get("RateLimit-Limit")
+			if actual != "2" {
+				t.Errorf("Expected %q got %q", "2", actual)
+			}
+
+			actual = rs.Header.Get("RateLimit-Remaining")
+			if actual != tt.expectedRemaining {
+				t.Errorf("Expected %q got %q", tt.expectedRemaining, actual)
+			}
+
+			actual = rs.Header.Get("Retry-After")
+			if actual != tt.expectedRetryAfter {
+				t.Errorf("Expected %q got %q", tt.expectedRetryAfter, actual)
+			}
+		})
+	}
+}
+
+func TestRateLimiterEvictsIdleKeys(t *testing.T) {
+	now := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	rl := NewRateLimiter(1, time.Second, KeyByIP)
+	rl.now = func() time.Time { return now }
+
+	rl.Allow("1.1.1.1")
+	now = now.Add(rl.IdleTimeout)
+	rl.Allow("2.2.2.2")
+
+	if _, ok := rl.buckets["1.1.1.1"]; ok {
+		t.Error("Expected idle key to be evicted")
+	}
+	if _, ok := rl.buckets["2.2.2.2"]; !ok {
+		t.Error("Expected active key to be kept")
+	}
+}
+
+func TestNewRateLimiterInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		period time.Duration
+	}{
+		{"Zero limit", 0, time.Second},
+		{"Negative limit", -1, time.Second},
+		{"Zero period", 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected a panic")
+				}
+			}()
+
+			NewRateLimiter(tt.limit, tt.period, KeyByIP)
+		})
+	}
+}
+
+func TestKeyFuncs(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "1.1.1.1:1234"
+
+	actual := KeyByIP(req)
+	if actual != "1.1.1.1" {
+		t.Errorf("Expected %q got %q", "1.1.1.1", actual)
+	}
+
+	actual = KeyByCookie("session")(req)
+	if actual != "1.1.1.1" {
+		t.Errorf("Expected %q got %q", "1.1.1.1", actual)
+	}
+
+	req.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+	actual = KeyByCookie("session")(req)
+	if actual != "cookie:abc" {
+		t.Errorf("Expected %q got %q", "cookie:abc", actual)
+	}
+
+	actual = KeyByUser(func(*http.Request) string { return "42" })(req)
+	if actual != "user:42" {
+		t.Errorf("Expected %q got %q", "user:42", actual)
+	}
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/tomascaslo/godinez"
+	"html/template"
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// PanicHook is called with every recovered panic, e.g. to report it to an
+// error tracker. stack is the stack trace of the panicking goroutine.
+type PanicHook func(r *http.Request, err interface{}, stack []byte)
+
+// RecoverOptions configures the RecoverPanicWith middleware.
+type RecoverOptions struct {
+	// Development renders a page with the panic, the stack trace and the
+	// request instead of a plain 500. Never enable it in production.
+	Development bool
+	// Hooks are called in order before the response is written.
+	Hooks []PanicHook
+}
+
+// RecoverPanicWith recovers panics of the next handlers, logs them to the
+// error logger of lh and responds with a 500 unless the response was
+// already started. In that case the connection is aborted, so the client
+// does not mistake the partial response for a complete one.
+//
+// http.ErrAbortHandler is panicked again, as net/http expects it to abort
+// the request silently. A *PanicError is reported with its own value and
+// stack.
+func RecoverPanicWith(lh logHolder, opts RecoverOptions) Mw {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sw := newStatusWriter(w)
+			defer func() {
+				err := recover()
+				if err == nil {
+					return
+				}
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				stack := debug.Stack()
+				if pe, ok := err.(*PanicError); ok {
+					err, stack = pe.Value, pe.Stack
+				}
+				panics.Inc()
+				for _, hook := range opts.Hooks {
+					hook(r, err, stack)
+				}
+
+				if sw.wroteHeader {
+					lh.GetErrorLogger().Output(2, fmt.Sprintf("panic after the response started: %v\n%s", err, stack))
+					panic(http.ErrAbortHandler)
+				}
+
+				w.Header().Set("Connection", "close")
+				if opts.Development {
+					lh.GetErrorLogger().Output(2, fmt.Sprintf("%v\n%s", err, stack))
+					renderPanicPage(w, r, err, stack)
+					return
+				}
+				godinez.ServerError(lh.GetErrorLogger(), w, fmt.Errorf("%v", err))
+			}()
+
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
+
+var panicPage = template.Must(template.New("panic").Parse(`<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>panic: {{.Error}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+h1 { color: #b00020; }
+pre { background: #f5f5f5; padding: 1em; overflow: auto; }
+td { padding: 0.2em 1em 0.2em 0; vertical-align: top; }
+</style>
+</head>
+<body>
+<h1>panic: {{.Error}}</h1>
+<h2>{{.Method}} {{.URL}}</h2>
+<table>
+<tr><td>Remote address</td><td>{{.RemoteAddr}}</td></tr>
+{{range .Headers}}<tr><td>{{.Name}}</td><td>{{.Value}}</td></tr>
+{{end}}</table>
+<h2>Stack trace</h2>
+<pre>{{.Stack}}</pre>
+</body>
+</html>
+`))
+
+type header struct {
+	Name  string
+	Value string
+}
+
+// renderPanicPage writes the development page of RecoverOptions. Cookies
+// and credentials are left out of the headers shown.
+func renderPanicPage(w http.ResponseWriter, r *http.Request, err interface{}, stack []byte) {
+	headers := []header{}
+	for name, values := range r.Header {
+		if name == "Cookie" || name == "Authorization" {
+			continue
+		}
+		headers = append(headers, header{name, strings.Join(values, ", ")})
+	}
+	sort.Slice(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusInternalServerError)
+	panicPage.Execute(w, struct {
+		Error      string
+		Method     string
+		URL        string
+		RemoteAddr string
+		Headers    []header
+		Stack      string
+	}{fmt.Sprint(err), r.Method, r.URL.String(), r.RemoteAddr, headers, string(stack)})
+}
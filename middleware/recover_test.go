@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecoverPanicWith(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           RecoverOptions
+		handler        http.HandlerFunc
+		expectedStatus int
+		expectedBody   string
+		expectedPanic  interface{}
+	}{
+		{
+			"Production",
+			RecoverOptions{},
+			func(w http.ResponseWriter, r *http.Request) { panic("boom") },
+			http.StatusInternalServerError,
+			http.StatusText(http.StatusInternalServerError),
+			nil,
+		},
+		{
+			"Development page",
+			RecoverOptions{Development: true},
+			func(w http.ResponseWriter, r *http.Request) { panic("<boom>") },
+			http.StatusInternalServerError,
+			"<h1>panic: &lt;boom&gt;</h1>",
+			nil,
+		},
+		{
+			"Abort handler",
+			RecoverOptions{},
+			func(w http.ResponseWriter, r *http.Request) { panic(http.ErrAbortHandler) },
+			http.StatusOK,
+			"",
+			http.ErrAbortHandler,
+		},
+		{
+			"Response already started",
+			RecoverOptions{Development: true},
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("partial"))
+				panic(errors.New("boom"))
+			},
+			http.StatusOK,
+			"partial",
+			http.ErrAbortHandler,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lh := &mockLogHolder{errorLog: log.New(ioutil.Discard, "", 0)}
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/posts?page=2", nil)
+			r.Header.Set("Cookie", "session=secret")
+
+			func() {
+				defer func() {
+					if err := recover(); err != tt.expectedPanic {
+						t.Errorf("Expected panic %v got %v", tt.expectedPanic, err)
+					}
+				}()
+				RecoverPanicWith(lh, tt.opts)(tt.handler).ServeHTTP(rr, r)
+			}()
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected %d got %d", tt.expectedStatus, rr.Code)
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("Expected %q in %q", tt.expectedBody, rr.Body.String())
+			}
+			if strings.Contains(rr.Body.String(), "secret") {
+				t.Errorf("Expected cookies to be left out of %q", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestRecoverPanicHooks(t *testing.T) {
+	logBuf := new(bytes.Buffer)
+	lh := &mockLogHolder{errorLog: log.New(logBuf, "", 0)}
+	var reported interface{}
+	var stack []byte
+	hook := func(r *http.Request, err interface{}, s []byte) {
+		reported, stack = err, s
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	RecoverPanicWith(lh, RecoverOptions{Hooks: []PanicHook{hook}})(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if reported != "boom" {
+		t.Errorf("Expected %q got %v", "boom", reported)
+	}
+	if !bytes.Contains(stack, []byte("TestRecoverPanicHooks")) {
+		t.Errorf("Expected the stack trace of the panic got %s", stack)
+	}
+	if !strings.Contains(logBuf.String(), "boom") {
+		t.Errorf("Expected %q to be logged got %q", "boom", logBuf.String())
+	}
+}
+
+func TestRecoverPanicHijack(t *testing.T) {
+	lh := &mockLogHolder{errorLog: log.New(ioutil.Discard, "", 0)}
+	h := RecoverPanic(lh)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			t.Error("Expected the writer to implement http.Hijacker")
+			return
+		}
+		conn, rw, err := hj.Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+		rw.Flush()
+	}))
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	rs, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rs.Body.Close()
+	body, err := ioutil.ReadAll(rs.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "hijacked" {
+		t.Errorf("Expected %q got %q", "hijacked", body)
+	}
+}
+
+func TestStatusWriterReadFrom(t *testing.T) {
+	rr := httptest.NewRecorder()
+	sw := newStatusWriter(rr)
+
+	n, err := sw.ReadFrom(strings.NewReader("Hello"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 5 || sw.size != 5 {
+		t.Errorf("Expected %d got %d and %d", 5, n, sw.size)
+	}
+	if rr.Body.String() != "Hello" {
+		t.Errorf("Expected %q got %q", "Hello", rr.Body.String())
+	}
+	if err := sw.Push("/app.css", nil); err != http.ErrNotSupported {
+		t.Errorf("Expected %v got %v", http.ErrNotSupported, err)
+	}
+}
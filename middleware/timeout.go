@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/tomascaslo/godinez"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// TimeoutOptions configures the TimeoutWith middleware.
+type TimeoutOptions struct {
+	// Duration bounds the execution of the next handlers.
+	Duration time.Duration
+	// Status is sent when Duration is exceeded, defaults to 503. Use 504
+	// for handlers proxying an upstream service.
+	Status int
+}
+
+// Timeout responds with a 503 when the next handlers take longer than d.
+// See TimeoutWith.
+func Timeout(d time.Duration) Mw {
+	return TimeoutWith(TimeoutOptions{Duration: d})
+}
+
+// TimeoutWith runs the next handlers with a context deadline of
+// opts.Duration. Handlers should watch r.Context() and stop once it is
+// done, their response is discarded after the deadline and the client
+// receives opts.Status instead.
+//
+// The response is buffered until the handlers return, so nothing reaches
+// the client before and streaming responses are not supported. Panics are
+// passed on to the serving goroutine as a *PanicError, holding the stack of
+// the handler, so RecoverPanic still handles them.
+func TimeoutWith(opts TimeoutOptions) Mw {
+	if opts.Status == 0 {
+		opts.Status = http.StatusServiceUnavailable
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), opts.Duration)
+			defer cancel()
+			r = r.WithContext(ctx)
+
+			tw := &timeoutWriter{h: http.Header{}}
+			done := make(chan struct{})
+			panicCh := make(chan interface{}, 1)
+			go func() {
+				defer func() {
+					if err := recover(); err != nil {
+						if err != http.ErrAbortHandler {
+							err = &PanicError{Value: err, Stack: debug.Stack()}
+						}
+						panicCh <- err
+					}
+				}()
+				next.ServeHTTP(tw, r)
+				close(done)
+			}()
+
+			select {
+			case err := <-panicCh:
+				panic(err)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				dst := w.Header()
+				for k, v := range tw.h {
+					dst[k] = v
+				}
+				if !tw.wroteHeader {
+					tw.status = http.StatusOK
+				}
+				w.WriteHeader(tw.status)
+				w.Write(tw.buf.Bytes())
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				tw.timedOut = true
+				godinez.ClientError(w, opts.Status)
+			}
+		})
+	}
+}
+
+// PanicError is a panic recovered in another goroutine, e.g. the handler
+// goroutine of TimeoutWith, and panicked again with the stack trace of
+// that goroutine. RecoverPanic reports Value and Stack.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (pe *PanicError) Error() string {
+	return fmt.Sprint(pe.Value)
+}
+
+// timeoutWriter buffers the response of TimeoutWith, so that handlers
+// still running after the deadline never touch the real writer.
+type timeoutWriter struct {
+	mu          sync.Mutex
+	h           http.Header
+	buf         bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.h
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if !tw.wroteHeader {
+		tw.status = http.StatusOK
+		tw.wroteHeader = true
+	}
+	return tw.buf.Write(b)
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.status = status
+	tw.wroteHeader = true
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           TimeoutOptions
+		handler        http.HandlerFunc
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			"Within the deadline",
+			TimeoutOptions{Duration: time.Second},
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Post", "1")
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte("created"))
+			},
+			http.StatusCreated,
+			"created",
+		},
+		{
+			"Deadline exceeded",
+			TimeoutOptions{Duration: 10 * time.Millisecond},
+			func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+				w.Write([]byte("too late"))
+			},
+			http.StatusServiceUnavailable,
+			http.StatusText(http.StatusServiceUnavailable),
+		},
+		{
+			"Gateway timeout",
+			TimeoutOptions{Duration: 10 * time.Millisecond, Status: http.StatusGatewayTimeout},
+			func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(50 * time.Millisecond)
+				w.Header().Set("X-Post", "1")
+				w.Write([]byte("too late"))
+			},
+			http.StatusGatewayTimeout,
+			http.StatusText(http.StatusGatewayTimeout),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			TimeoutWith(tt.opts)(tt.handler).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected %d got %d", tt.expectedStatus, rr.Code)
+			}
+			if body := strings.TrimSuffix(rr.Body.String(), "\n"); body != tt.expectedBody {
+				t.Errorf("Expected %q got %q", tt.expectedBody, body)
+			}
+			// Let handlers still running write after the timeout.
+			time.Sleep(60 * time.Millisecond)
+		})
+	}
+}
+
+func TestTimeoutPanic(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	defer func() {
+		pe, ok := recover().(*PanicError)
+		if !ok || pe.Value != "boom" {
+			t.Errorf("Expected panic %q got %v", "boom", pe)
+		}
+	}()
+
+	Timeout(time.Second)(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
+
+func panickingHandler(w http.ResponseWriter, r *http.Request) {
+	panic("boom")
+}
+
+func TestTimeoutPanicStack(t *testing.T) {
+	lh := &mockLogHolder{errorLog: log.New(ioutil.Discard, "", 0)}
+	var value interface{}
+	var stack []byte
+	hook := func(r *http.Request, err interface{}, s []byte) {
+		value, stack = err, s
+	}
+	h := NewEme(RecoverPanicWith(lh, RecoverOptions{Hooks: []PanicHook{hook}}), Timeout(time.Second)).ApplyFunc(panickingHandler)
+	rr := httptest.NewRecorder()
+
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected %d got %d", http.StatusInternalServerError, rr.Code)
+	}
+	if value != "boom" {
+		t.Errorf("Expected %q got %v", "boom", value)
+	}
+	if !strings.Contains(string(stack), "panickingHandler") {
+		t.Errorf("Expected the stack of the handler got %s", stack)
+	}
+}
+
+func TestTimeoutAbortHandler(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+	defer func() {
+		if err := recover(); err != http.ErrAbortHandler {
+			t.Errorf("Expected %v got %v", http.ErrAbortHandler, err)
+		}
+	}()
+
+	Timeout(time.Second)(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
+
+func TestEmeWithTimeout(t *testing.T) {
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+			w.Write([]byte("done"))
+		}
+	})
+	base := NewEme(Timeout(time.Second))
+	short := base.With(Timeout(10 * time.Millisecond))
+	rr := httptest.NewRecorder()
+
+	short.Apply(slow).ServeHTTP(rr, httptest.NewRequest("GET", "/reports", nil))
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected %d got %d", http.StatusServiceUnavailable, rr.Code)
+	}
+	if len(base.mws) != 1 {
+		t.Errorf("Expected With to leave the original chain unchanged")
+	}
+}
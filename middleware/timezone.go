@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"github.com/tomascaslo/godinez"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// TimezoneOptions configures the Timezone middleware.
+type TimezoneOptions struct {
+	// UserTimezone returns the IANA time zone saved by the user, e.g.
+	// "America/Mexico_City", or an empty string if there is none.
+	UserTimezone func(*http.Request) string
+	// CookieName is the cookie holding the time zone of the browser,
+	// usually set with Intl.DateTimeFormat().resolvedOptions().timeZone.
+	CookieName string
+	// Default is used when no time zone is found, defaults to UTC.
+	Default *time.Location
+}
+
+var locations sync.Map
+
+// loadLocation caches time.LoadLocation, which reads the tz database.
+func loadLocation(name string) (*time.Location, bool) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), true
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, false
+	}
+	locations.Store(name, loc)
+	return loc, true
+}
+
+// Timezone stores the time zone of each request in its context, see
+// godinez.LocationFromRequest. The user preference wins over the cookie.
+func Timezone(opts TimezoneOptions) Mw {
+	if opts.Default == nil {
+		opts.Default = time.UTC
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			loc := opts.Default
+
+			var names []string
+			if opts.UserTimezone != nil {
+				names = append(names, opts.UserTimezone(r))
+			}
+			if opts.CookieName != "" {
+				if c, err := r.Cookie(opts.CookieName); err == nil {
+					// Browsers escape the "/" in names like America/Mexico_City
+					if name, err := url.QueryUnescape(c.Value); err == nil {
+						names = append(names, name)
+					}
+				}
+			}
+			for _, name := range names {
+				if name == "" {
+					continue
+				}
+				if l, ok := loadLocation(name); ok {
+					loc = l
+					break
+				}
+			}
+
+			ctx := context.WithValue(r.Context(), godinez.ContextKeyLocation, loc)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
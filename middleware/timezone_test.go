@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"github.com/tomascaslo/godinez"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTimezone(t *testing.T) {
+	tests := []struct {
+		name         string
+		userTimezone string
+		cookie       string
+		expected     string
+	}{
+		{"Default", "", "", "UTC"},
+		{"Cookie", "", "America%2FMexico_City", "America/Mexico_City"},
+		{"User preference wins", "Europe/Madrid", "America/Mexico_City", "Europe/Madrid"},
+		{"Invalid time zone", "Nowhere/City", "", "UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actual string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actual = godinez.LocationFromRequest(r).String()
+			})
+			opts := TimezoneOptions{
+				UserTimezone: func(*http.Request) string { return tt.userTimezone },
+				CookieName:   "tz",
+			}
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "tz", Value: tt.cookie})
+			}
+
+			Timezone(opts)(next).ServeHTTP(rr, req)
+
+			if actual != tt.expected {
+				t.Errorf("Expected %q got %q", tt.expected, actual)
+			}
+		})
+	}
+}
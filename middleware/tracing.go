@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"github.com/tomascaslo/godinez/tracing"
+	"net/http"
+)
+
+// Trace wraps each request in a span of t, or tracing.Default if nil,
+// continuing the trace of the incoming traceparent header. The spans of
+// Render and the forms package become its children. Panics are recorded
+// as errors with a 500 status unless the response was already started.
+func Trace(t *tracing.Tracer) Mw {
+	if t == nil {
+		t = tracing.Default
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, span := t.Start(tracing.Extract(r.Context(), r.Header), "HTTP "+r.Method)
+			span.SetAttribute("http.method", r.Method)
+			span.SetAttribute("http.target", r.URL.RequestURI())
+			sw := newStatusWriter(w)
+			r = r.WithContext(ctx)
+			defer func() {
+				rec := recover()
+				if r.Pattern != "" {
+					span.Name = r.Pattern
+					span.SetAttribute("http.route", r.Pattern)
+				}
+				status := panicStatus(sw, rec)
+				span.SetAttribute("http.status_code", status)
+				span.SetAttribute("http.response_size", sw.size)
+				if rec != nil {
+					span.RecordError(fmt.Errorf("panic: %v", rec))
+				} else if status >= http.StatusInternalServerError {
+					span.RecordError(errors.New(http.StatusText(status)))
+				}
+				span.Finish()
+				if rec != nil {
+					panic(rec)
+				}
+			}()
+
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
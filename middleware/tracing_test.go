@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"github.com/tomascaslo/godinez/forms"
+	"github.com/tomascaslo/godinez/tracing"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	exporter := &tracing.InMemoryExporter{}
+	tracer := tracing.NewTracer(exporter)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /signup", func(w http.ResponseWriter, r *http.Request) {
+		var dst struct {
+			Email string `form:"email" validate:"required"`
+		}
+		forms.Decode(r, &dst)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	})
+	r := httptest.NewRequest("POST", "/signup", strings.NewReader("email="))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	Trace(tracer)(mux).ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected %d spans got %d", 2, len(spans))
+	}
+	decode, request := spans[0], spans[1]
+	if request.Name != "POST /signup" {
+		t.Errorf("Expected %q got %q", "POST /signup", request.Name)
+	}
+	if request.Attributes["http.status_code"] != http.StatusUnprocessableEntity {
+		t.Errorf("Expected %d got %v", http.StatusUnprocessableEntity, request.Attributes["http.status_code"])
+	}
+	if request.SpanContext.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected the incoming trace id got %q", request.SpanContext.TraceID)
+	}
+	if decode.Name != "forms.Decode" || decode.Parent != request.SpanContext.SpanID {
+		t.Errorf("Expected forms.Decode to be a child of the request span")
+	}
+	if decode.Attributes["form.valid"] != false {
+		t.Errorf("Expected %v got %v", false, decode.Attributes["form.valid"])
+	}
+}
+
+func TestTracePanic(t *testing.T) {
+	exporter := &tracing.InMemoryExporter{}
+	lh := &mockLogHolder{errorLog: log.New(ioutil.Discard, "", 0)}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	rr := httptest.NewRecorder()
+
+	NewEme(RecoverPanic(lh), Trace(tracing.NewTracer(exporter))).Apply(next).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected %d got %d", http.StatusInternalServerError, rr.Code)
+	}
+	spans := exporter.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected %d spans got %d", 1, len(spans))
+	}
+	if spans[0].Attributes["http.status_code"] != http.StatusInternalServerError {
+		t.Errorf("Expected %d got %v", http.StatusInternalServerError, spans[0].Attributes["http.status_code"])
+	}
+	if spans[0].Error != "panic: boom" {
+		t.Errorf("Expected %q got %q", "panic: boom", spans[0].Error)
+	}
+}
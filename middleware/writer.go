@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+)
+
+// statusWriter records the status and size of a response for the
+// middleware that report them. It forwards Flush, Hijack, ReadFrom and
+// Push to the underlying writer, so that wrapping a handler does not
+// break websockets, sendfile or HTTP/2 pushes.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	wroteHeader bool
+}
+
+func newStatusWriter(w http.ResponseWriter) *statusWriter {
+	return &statusWriter{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	if !sw.wroteHeader {
+		sw.status = status
+		sw.wroteHeader = true
+	}
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	sw.wroteHeader = true
+	n, err := sw.ResponseWriter.Write(b)
+	sw.size += n
+	return n, err
+}
+
+func (sw *statusWriter) Flush() {
+	sw.wroteHeader = true
+	if f, ok := sw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hands the connection over to the handler, which then owns the
+// response. The status is recorded as 101 unless one was written.
+func (sw *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := sw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("middleware: the ResponseWriter does not implement http.Hijacker")
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && !sw.wroteHeader {
+		sw.status = http.StatusSwitchingProtocols
+		sw.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// ReadFrom lets io.Copy use the underlying io.ReaderFrom, e.g. sendfile.
+func (sw *statusWriter) ReadFrom(src io.Reader) (int64, error) {
+	sw.wroteHeader = true
+	var n int64
+	var err error
+	if rf, ok := sw.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(src)
+	} else {
+		n, err = io.Copy(writerOnly{sw.ResponseWriter}, src)
+	}
+	sw.size += int(n)
+	return n, err
+}
+
+func (sw *statusWriter) Push(target string, opts *http.PushOptions) error {
+	if p, ok := sw.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// panicStatus returns the status recorded by sw, or 500 when the handler
+// panicked with rec before starting the response, since that is what
+// RecoverPanic responds with.
+func panicStatus(sw *statusWriter, rec interface{}) int {
+	if rec != nil && !sw.wroteHeader {
+		return http.StatusInternalServerError
+	}
+	return sw.status
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
+
+// writerOnly hides the io.ReaderFrom of a writer from io.Copy.
+type writerOnly struct {
+	io.Writer
+}
@@ -0,0 +1,121 @@
+package godinez
+
+import (
+	"github.com/golangcollege/sessions"
+	"github.com/justinas/nosurf"
+	"github.com/tomascaslo/godinez/i18n"
+	"net/http"
+	"time"
+)
+
+// FlashKey is the session key AddDefaultData pops the flash message from.
+var FlashKey = "flash"
+
+// Optional capabilities of template data. AddDefaultData only sets the
+// data of the interfaces implemented, so any type can be rendered.
+type CSRFSetter interface {
+	SetCSRFToken(string)
+}
+
+type YearSetter interface {
+	SetCurrentYear(int)
+}
+
+type AuthSetter interface {
+	SetIsAuthenticated(bool)
+}
+
+type FlashSetter interface {
+	SetFlash(string)
+}
+
+type LocaleSetter interface {
+	SetLocale(string)
+}
+
+type LocationSetter interface {
+	SetLocation(*time.Location)
+}
+
+func (td *TemplateData) SetCSRFToken(token string) {
+	td.CSRFToken = token
+}
+
+func (td *TemplateData) SetCurrentYear(year int) {
+	td.CurrentYear = year
+}
+
+func (td *TemplateData) SetIsAuthenticated(isAuthenticated bool) {
+	td.IsAuthenticated = isAuthenticated
+}
+
+func (td *TemplateData) SetFlash(flash string) {
+	td.Flash = flash
+}
+
+func (td *TemplateData) SetLocale(locale string) {
+	td.Locale = locale
+}
+
+func (td *TemplateData) SetLocation(loc *time.Location) {
+	td.Location = loc
+}
+
+// Page wraps the data of a page with TemplateData, so that templates can
+// use both {{.CSRFToken}} and {{.Data.Title}}.
+type Page[T any] struct {
+	TemplateData
+	Data T
+}
+
+// NewPage initializes a *Page holding data.
+func NewPage[T any](data T) *Page[T] {
+	return &Page[T]{Data: data}
+}
+
+// errMissingSession is the message of the panic of sessions when the
+// request did not go through Session.Enable.
+const errMissingSession = "session: cache not present in request context"
+
+// popFlash pops the flash message from session, or returns "" when the
+// session is not loaded for r, e.g. on routes without Session.Enable.
+func popFlash(session *sessions.Session, r *http.Request) (flash string) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			if err, ok := rec.(error); !ok || err.Error() != errMissingSession {
+				panic(rec)
+			}
+		}
+	}()
+	return session.PopString(r, FlashKey)
+}
+
+// addCapabilities sets the default data of the capabilities implemented
+// by data, including the values of the registered providers.
+func addCapabilities(app application, data interface{}, r *http.Request) {
+	if s, ok := data.(CSRFSetter); ok {
+		s.SetCSRFToken(nosurf.Token(r))
+	}
+	if s, ok := data.(YearSetter); ok {
+		s.SetCurrentYear(now().Year())
+	}
+	if s, ok := data.(AuthSetter); ok {
+		s.SetIsAuthenticated(app.IsAuthenticated(r))
+	}
+	if s, ok := data.(FlashSetter); ok {
+		// The flash is popped by the first call, so a later Render after
+		// AddDefaultData must not overwrite it with an empty one.
+		if session := app.GetSession(); session != nil {
+			if flash := popFlash(session, r); flash != "" {
+				s.SetFlash(flash)
+			}
+		}
+	}
+	if s, ok := data.(LocaleSetter); ok {
+		s.SetLocale(i18n.LocaleFromContext(r.Context()))
+	}
+	if s, ok := data.(LocationSetter); ok {
+		s.SetLocation(LocationFromRequest(r))
+	}
+	addProvided(data, r)
+}
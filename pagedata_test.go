@@ -0,0 +1,136 @@
+package godinez
+
+import (
+	"bytes"
+	"github.com/golangcollege/sessions"
+	"github.com/tomascaslo/godinez/i18n"
+	"github.com/tomascaslo/godinez/metrics"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type yearOnly struct {
+	Year int
+}
+
+func (y *yearOnly) SetCurrentYear(year int) {
+	y.Year = year
+}
+
+func TestAddDefaultDataCapabilities(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2019, time.May, 1, 0, 0, 0, 0, time.UTC) }
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(i18n.WithLocale(r.Context(), "es"))
+	app := &mockApplication{spy: &addDefaultDataSpy{}}
+
+	t.Run("Single capability", func(t *testing.T) {
+		data := &yearOnly{}
+
+		AddDefaultData(app, data, r)
+
+		if data.Year != 2019 {
+			t.Errorf("Expected %d got %d", 2019, data.Year)
+		}
+	})
+
+	t.Run("TemplateData", func(t *testing.T) {
+		data := &TemplateData{}
+
+		AddDefaultData(app, data, r)
+
+		if data.CurrentYear != 2019 {
+			t.Errorf("Expected %d got %d", 2019, data.CurrentYear)
+		}
+		if data.Locale != "es" {
+			t.Errorf("Expected %q got %q", "es", data.Locale)
+		}
+		if data.Location != time.UTC {
+			t.Errorf("Expected %q got %q", time.UTC, data.Location)
+		}
+	})
+
+	t.Run("No capabilities", func(t *testing.T) {
+		AddDefaultData(app, struct{}{}, r)
+	})
+}
+
+func TestRenderPage(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2019, time.May, 1, 0, 0, 0, 0, time.UTC) }
+	templ := template.Must(template.New("page").Parse(`{{.Data.Title}} {{.CurrentYear}}`))
+	app := &mockApplication{
+		spy:              &addDefaultDataSpy{},
+		funcReturnValues: map[string]interface{}{"getTemplateCache": templ},
+	}
+	rr := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+
+	Render(app, NewPage(struct{ Title string }{"Home"}), rr, r, "page")
+
+	expected := "Home 2019"
+	if rr.Body.String() != expected {
+		t.Errorf("Expected %q got %q", expected, rr.Body.String())
+	}
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected %d got %d", http.StatusOK, rr.Code)
+	}
+
+	buf := new(bytes.Buffer)
+	metrics.Default.WriteTo(buf)
+	expectedMetric := `godinez_template_render_duration_seconds_count{template="page"}`
+	if !strings.Contains(buf.String(), expectedMetric) {
+		t.Errorf("Expected %q in %q", expectedMetric, buf.String())
+	}
+}
+
+func TestAddDefaultDataFlash(t *testing.T) {
+	session := sessions.New([]byte("u46IpCV9y5Vlur8YvODJEhgOY8m9JVE4"))
+	app := NewApp(WithSession(session))
+
+	t.Run("Session not enabled", func(t *testing.T) {
+		data := &TemplateData{}
+
+		AddDefaultData(app, data, httptest.NewRequest("GET", "/", nil))
+
+		if data.Flash != "" {
+			t.Errorf("Expected %q got %q", "", data.Flash)
+		}
+	})
+
+	t.Run("Session enabled", func(t *testing.T) {
+		data := &TemplateData{}
+		h := session.Enable(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session.Put(r, FlashKey, "Saved")
+			AddDefaultData(app, data, r)
+		}))
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+		if data.Flash != "Saved" {
+			t.Errorf("Expected %q got %q", "Saved", data.Flash)
+		}
+	})
+
+	t.Run("AddDefaultData before Render", func(t *testing.T) {
+		templ := template.Must(template.New("page").Parse(`[{{.Flash}}] {{.Data}}`))
+		app := NewApp(WithSession(session), WithTemplateCache(map[string]*template.Template{"page": templ}))
+		rr := httptest.NewRecorder()
+		h := session.Enable(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session.Put(r, FlashKey, "Saved")
+			page := NewPage("Hello")
+			AddDefaultData(app, page, r)
+			Render(app, page, w, r, "page")
+		}))
+
+		h.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+		if rr.Body.String() != "[Saved] Hello" {
+			t.Errorf("Expected %q got %q", "[Saved] Hello", rr.Body.String())
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package godinez
+
+import (
+	"github.com/tomascaslo/godinez/forms"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var SlugRX = regexp.MustCompile("^[a-z0-9]+(?:-[a-z0-9]+)*$")
+
+// param looks up name in the path values set by http.ServeMux patterns
+// such as "/snippet/{id}" and falls back to the URL query.
+// The returned bool reports whether the value came from the path.
+func param(r *http.Request, name string) (string, bool) {
+	if value := r.PathValue(name); value != "" {
+		return value, true
+	}
+	return r.URL.Query().Get(name), false
+}
+
+// paramError sends a 404 when a path value is malformed, since the URL
+// does not point to any resource, and a 400 when a query value is.
+func paramError(w http.ResponseWriter, fromPath bool) {
+	if fromPath {
+		NotFound(w)
+		return
+	}
+	ClientError(w, http.StatusBadRequest)
+}
+
+// ParamInt returns the named parameter as a positive integer.
+// If the parameter is missing or invalid an error response is sent
+// and false is returned, so handlers only need to return.
+func ParamInt(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	value, fromPath := param(r, name)
+	id, err := strconv.Atoi(value)
+	if err != nil || id < 1 {
+		paramError(w, fromPath)
+		return 0, false
+	}
+	return id, true
+}
+
+// ParamUUID returns the named parameter if it is a UUID in its canonical
+// textual form, e.g. 123e4567-e89b-12d3-a456-426614174000.
+// The value is returned in lower case.
+func ParamUUID(w http.ResponseWriter, r *http.Request, name string) (string, bool) {
+	value, fromPath := param(r, name)
+	if !forms.UUIDRX.MatchString(value) {
+		paramError(w, fromPath)
+		return "", false
+	}
+	return strings.ToLower(value), true
+}
+
+// ParamSlug returns the named parameter if it is a slug made of lower case
+// letters, digits and single dashes, e.g. "my-first-post".
+func ParamSlug(w http.ResponseWriter, r *http.Request, name string) (string, bool) {
+	value, fromPath := param(r, name)
+	if !SlugRX.MatchString(value) {
+		paramError(w, fromPath)
+		return "", false
+	}
+	return value, true
+}
@@ -0,0 +1,112 @@
+package godinez
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParamInt(t *testing.T) {
+	tests := []struct {
+		name           string
+		pattern        string
+		url            string
+		expectedValue  int
+		expectedOK     bool
+		expectedStatus int
+	}{
+		{"Valid path value", "/snippet/{id}", "/snippet/12", 12, true, http.StatusOK},
+		{"Invalid path value", "/snippet/{id}", "/snippet/abc", 0, false, http.StatusNotFound},
+		{"Negative path value", "/snippet/{id}", "/snippet/-1", 0, false, http.StatusNotFound},
+		{"Valid query value", "/snippet", "/snippet?id=7", 7, true, http.StatusOK},
+		{"Invalid query value", "/snippet", "/snippet?id=x", 0, false, http.StatusBadRequest},
+		{"Missing query value", "/snippet", "/snippet", 0, false, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actualValue int
+			var actualOK bool
+			mux := http.NewServeMux()
+			mux.HandleFunc(tt.pattern, func(w http.ResponseWriter, r *http.Request) {
+				actualValue, actualOK = ParamInt(w, r, "id")
+			})
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest("GET", tt.url, nil))
+
+			if actualValue != tt.expectedValue {
+				t.Errorf("Expected %d got %d", tt.expectedValue, actualValue)
+			}
+			if actualOK != tt.expectedOK {
+				t.Errorf("Expected %t got %t", tt.expectedOK, actualOK)
+			}
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected %d got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestParamUUID(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          string
+		expectedValue  string
+		expectedStatus int
+	}{
+		{"Valid UUID", "123E4567-E89B-12D3-A456-426614174000", "123e4567-e89b-12d3-a456-426614174000", http.StatusOK},
+		{"Invalid UUID", "123e4567", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actual string
+			mux := http.NewServeMux()
+			mux.HandleFunc("/user/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+				actual, _ = ParamUUID(w, r, "uuid")
+			})
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest("GET", fmt.Sprintf("/user/%s", tt.value), nil))
+
+			if actual != tt.expectedValue {
+				t.Errorf("Expected %q got %q", tt.expectedValue, actual)
+			}
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected %d got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestParamSlug(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          string
+		expectedValue  string
+		expectedStatus int
+	}{
+		{"Valid slug", "my-first-post", "my-first-post", http.StatusOK},
+		{"Upper case slug", "My-Post", "", http.StatusNotFound},
+		{"Double dash slug", "my--post", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actual string
+			mux := http.NewServeMux()
+			mux.HandleFunc("/post/{slug}", func(w http.ResponseWriter, r *http.Request) {
+				actual, _ = ParamSlug(w, r, "slug")
+			})
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest("GET", fmt.Sprintf("/post/%s", tt.value), nil))
+
+			if actual != tt.expectedValue {
+				t.Errorf("Expected %q got %q", tt.expectedValue, actual)
+			}
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected %d got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package godinez
+
+import (
+	"net/http"
+	"sync"
+)
+
+// DataProvider returns a piece of data added to every rendered template
+// under key, e.g. the current user. An empty key adds nothing.
+type DataProvider func(r *http.Request) (key string, value interface{})
+
+// DefaultsSetter is implemented by template data receiving the values of
+// the registered providers.
+type DefaultsSetter interface {
+	SetDefault(key string, value interface{})
+}
+
+type namedProvider struct {
+	name     string
+	provider DataProvider
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   []namedProvider
+)
+
+// RegisterProvider adds a provider called by AddDefaultData and Render.
+// Providers run in registration order and registering a name again
+// replaces its provider.
+func RegisterProvider(name string, p DataProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	for i := range providers {
+		if providers[i].name == name {
+			providers[i].provider = p
+			return
+		}
+	}
+	providers = append(providers, namedProvider{name, p})
+}
+
+// RemoveProvider removes the provider registered as name.
+func RemoveProvider(name string) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	for i := range providers {
+		if providers[i].name == name {
+			providers = append(providers[:i], providers[i+1:]...)
+			return
+		}
+	}
+}
+
+// ProvidedData returns the merged values of the registered providers.
+func ProvidedData(r *http.Request) map[string]interface{} {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	values := make(map[string]interface{}, len(providers))
+	for _, p := range providers {
+		if key, value := p.provider(r); key != "" {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// addProvided merges the values of the providers into data when it is a
+// map or implements DefaultsSetter. Keys already in a map are kept.
+func addProvided(data interface{}, r *http.Request) {
+	switch data := data.(type) {
+	case map[string]interface{}:
+		for k, v := range ProvidedData(r) {
+			if _, ok := data[k]; !ok {
+				data[k] = v
+			}
+		}
+	case DefaultsSetter:
+		for k, v := range ProvidedData(r) {
+			data.SetDefault(k, v)
+		}
+	}
+}
+
+// SetDefault makes value available to templates as {{.Defaults.key}}.
+func (td *TemplateData) SetDefault(key string, value interface{}) {
+	if td.Defaults == nil {
+		td.Defaults = map[string]interface{}{}
+	}
+	td.Defaults[key] = value
+}
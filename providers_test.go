@@ -0,0 +1,62 @@
+package godinez
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRegisterProvider(t *testing.T) {
+	defer func() { providers = nil }()
+	RegisterProvider("version", func(r *http.Request) (string, interface{}) { return "Version", "1.0.0" })
+	RegisterProvider("user", func(r *http.Request) (string, interface{}) { return "", nil })
+	RegisterProvider("version", func(r *http.Request) (string, interface{}) { return "Version", "1.1.0" })
+	RegisterProvider("nav", func(r *http.Request) (string, interface{}) { return "Nav", []string{"Home"} })
+	r := httptest.NewRequest("GET", "/", nil)
+
+	expected := map[string]interface{}{"Version": "1.1.0", "Nav": []string{"Home"}}
+	if actual := ProvidedData(r); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v got %v", expected, actual)
+	}
+
+	RemoveProvider("nav")
+	expected = map[string]interface{}{"Version": "1.1.0"}
+	if actual := ProvidedData(r); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v got %v", expected, actual)
+	}
+}
+
+func TestRenderProvidedData(t *testing.T) {
+	defer func() { providers = nil }()
+	RegisterProvider("version", func(r *http.Request) (string, interface{}) { return "Version", "1.0.0" })
+	r := httptest.NewRequest("GET", "/", nil)
+	tests := []struct {
+		name     string
+		templ    string
+		data     interface{}
+		expected string
+	}{
+		{"TemplateData", `{{.Defaults.Version}}`, &TemplateData{}, "1.0.0"},
+		{"Page", `{{.Defaults.Version}} {{.Data}}`, NewPage("Home"), "1.0.0 Home"},
+		{"Map", `{{.Version}} {{.Title}}`, map[string]interface{}{"Title": "Home"}, "1.0.0 Home"},
+		{"Map keeps its keys", `{{.Version}}`, map[string]interface{}{"Version": "dev"}, "dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &mockApplication{
+				spy:              &addDefaultDataSpy{},
+				funcReturnValues: map[string]interface{}{"getTemplateCache": template.Must(template.New("page").Parse(tt.templ))},
+			}
+			rr := httptest.NewRecorder()
+
+			Render(app, tt.data, rr, r, "page")
+
+			if rr.Body.String() != tt.expected {
+				t.Errorf("Expected %q got %q", tt.expected, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package godinez
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// WithTimeouts sets the read, write and idle timeouts of the servers
+// created by NewServer.
+func WithTimeouts(read, write, idle time.Duration) Option {
+	return func(app *App) {
+		app.readTimeout = read
+		app.writeTimeout = write
+		app.idleTimeout = idle
+	}
+}
+
+// WithShutdownTimeout sets how long Serve waits for in-flight requests
+// and shutdown hooks before giving up.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(app *App) {
+		app.shutdownTimeout = d
+	}
+}
+
+// WithDrainDelay sets how long Shutdown keeps serving requests after
+// Readyz starts failing, so that load balancers notice it before the
+// server stops accepting connections. There is no delay by default.
+func WithDrainDelay(d time.Duration) Option {
+	return func(app *App) {
+		app.drainDelay = d
+	}
+}
+
+// OnShutdown registers fn to run after the server stops accepting
+// requests, e.g. to close the database. Hooks run in reverse order of
+// registration, like deferred calls.
+func (app *App) OnShutdown(fn func(ctx context.Context) error) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	app.shutdownHooks = append(app.shutdownHooks, fn)
+}
+
+// NewServer returns an *http.Server for h listening on addr, with the
+// app's timeouts, error logger and TLS config.
+func (app *App) NewServer(addr string, h http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           h,
+		ErrorLog:          app.errorLog,
+		ReadTimeout:       app.readTimeout,
+		ReadHeaderTimeout: app.readTimeout,
+		WriteTimeout:      app.writeTimeout,
+		IdleTimeout:       app.idleTimeout,
+		TLSConfig:         app.tlsConfig,
+	}
+}
+
+// Serve listens on addr and serves h until SIGINT or SIGTERM is received,
+// then shuts down gracefully. See ServeContext.
+func (app *App) Serve(addr string, h http.Handler) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return app.ServeContext(ctx, addr, h)
+}
+
+// ServeContext listens on addr and serves h, over HTTPS when the app has a
+// TLS config, until ctx is done. The server
+// then stops accepting connections, waits for in-flight requests and runs
+// the shutdown hooks within the shutdown timeout.
+func (app *App) ServeContext(ctx context.Context, addr string, h http.Handler) error {
+	srv := app.NewServer(addr, h)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return app.serve(ctx, srv, ln)
+}
+
+func (app *App) serve(ctx context.Context, srv *http.Server, ln net.Listener) error {
+	errCh := make(chan error, 1)
+	go func() {
+		app.infoLog.Printf("Starting server on %s", ln.Addr())
+		if srv.TLSConfig != nil {
+			errCh <- srv.ServeTLS(ln, "", "")
+			return
+		}
+		errCh <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	app.infoLog.Printf("Shutting down server on %s", ln.Addr())
+	return app.Shutdown(srv)
+}
+
+// Shutdown makes Readyz fail, waits for the drain delay, then gracefully
+// shuts down srv and runs the shutdown hooks, both within the shutdown
+// timeout. Errors of the hooks are joined.
+func (app *App) Shutdown(srv *http.Server) error {
+	app.shuttingDown.Store(true)
+	if app.drainDelay > 0 {
+		time.Sleep(app.drainDelay)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), app.shutdownTimeout)
+	defer cancel()
+
+	errs := []error{srv.Shutdown(ctx)}
+
+	app.mu.Lock()
+	hooks := append([]func(context.Context) error{}, app.shutdownHooks...)
+	app.mu.Unlock()
+	for i := len(hooks) - 1; i >= 0; i-- {
+		errs = append(errs, hooks[i](ctx))
+	}
+	return errors.Join(errs...)
+}
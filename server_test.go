@@ -0,0 +1,85 @@
+package godinez
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewServer(t *testing.T) {
+	app := NewApp(WithTimeouts(time.Second, 2*time.Second, 3*time.Second))
+
+	srv := app.NewServer(":4000", http.NotFoundHandler())
+
+	if srv.ReadTimeout != time.Second || srv.WriteTimeout != 2*time.Second || srv.IdleTimeout != 3*time.Second {
+		t.Errorf("Expected timeouts 1s, 2s and 3s got %s, %s and %s", srv.ReadTimeout, srv.WriteTimeout, srv.IdleTimeout)
+	}
+	if srv.ErrorLog != app.GetErrorLogger() {
+		t.Errorf("Expected the app's error logger")
+	}
+}
+
+func TestServeGracefulShutdown(t *testing.T) {
+	discard := log.New(ioutil.Discard, "", 0)
+	app := NewApp(WithInfoLogger(discard), WithErrorLogger(discard), WithShutdownTimeout(time.Second))
+	calls := []string{}
+	app.OnShutdown(func(ctx context.Context) error {
+		calls = append(calls, "db")
+		return nil
+	})
+	app.OnShutdown(func(ctx context.Context) error {
+		calls = append(calls, "logs")
+		return errors.New("flush failed")
+	})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("done"))
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- app.serve(ctx, app.NewServer("", h), ln) }()
+
+	resp := make(chan *http.Response, 1)
+	go func() {
+		rs, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			t.Error(err)
+		}
+		resp <- rs
+	}()
+
+	<-started
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	rs := <-resp
+	if rs == nil || rs.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the in-flight request to complete")
+	}
+	rs.Body.Close()
+
+	err = <-served
+	if err == nil || err.Error() != "flush failed" {
+		t.Errorf("Expected %q got %v", "flush failed", err)
+	}
+	expected := []string{"logs", "db"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected calls %v got %v", expected, calls)
+	}
+}
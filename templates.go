@@ -1,6 +1,7 @@
 package godinez
 
 import (
+	"github.com/tomascaslo/godinez/i18n"
 	"html/template"
 	"path/filepath"
 	"time"
@@ -11,17 +12,46 @@ type TemplateData struct {
 	CurrentYear     int    // The current year e.g. 2019
 	Flash           string // Flash message to show on website
 	IsAuthenticated bool
+	Locale          string                 // The locale negotiated by middleware.Locale e.g. "es"
+	Location        *time.Location         // The time zone set by middleware.Timezone
+	Defaults        map[string]interface{} // Values of the registered providers
 }
 
+// HumanDateLayout is the layout used by HumanDate and HumanDateIn.
+var HumanDateLayout = "02 Jan 2006 at 15:04"
+
 func HumanDate(t time.Time) string {
-	if t.IsZero() {
-		return ""
-	}
-	return t.UTC().Format("02 Jan 2006 at 15:04")
+	return HumanDateIn(t, time.UTC)
+}
+
+// HumanDateLocale is HumanDate with the layout and month names of locale,
+// taken from i18n.Default e.g. "02 ene 2019 a las 10:00" for "es".
+func HumanDateLocale(t time.Time, locale string) string {
+	return HumanDateLocaleIn(t, time.UTC, locale)
+}
+
+// Translate returns the message key of locale from i18n.Default.
+// Use it in templates as {{t .Locale "home.title"}}.
+func Translate(locale, key string, args ...interface{}) string {
+	return i18n.Default.Translate(locale, key, args...)
 }
 
 var functions = template.FuncMap{
-	"humanDate": HumanDate,
+	"checkbox":          Checkbox,
+	"csrfField":         CSRFField,
+	"fieldErrors":       FieldErrors,
+	"humanDate":         HumanDate,
+	"humanDateIn":       HumanDateIn,
+	"humanDateLocale":   HumanDateLocale,
+	"humanDateLocaleIn": HumanDateLocaleIn,
+	"input":             Input,
+	"options":           Options,
+	"radio":             Radio,
+	"relativeTime":      RelativeTime,
+	"select":            Select,
+	"t":                 Translate,
+	"textarea":          Textarea,
+	"timeAgo":           TimeAgo,
 }
 
 func NewTemplateCache(dir string) (map[string]*template.Template, error) {
@@ -0,0 +1,46 @@
+package godinez
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+	"time"
+)
+
+func TestHumanDateLocale(t *testing.T) {
+	date := time.Date(2019, time.January, 2, 15, 4, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		t        time.Time
+		locale   string
+		expected string
+	}{
+		{"English", date, "en", "02 Jan 2019 at 15:04"},
+		{"Spanish", date, "es", "02 ene 2019 a las 15:04"},
+		{"Zero time", time.Time{}, "es", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := HumanDateLocale(tt.t, tt.locale)
+			if actual != tt.expected {
+				t.Errorf("Expected %q got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTranslateTemplateFunc(t *testing.T) {
+	templ := template.Must(template.New("page").Funcs(functions).Parse(`{{t .Locale "humanDate"}}`))
+	buf := new(bytes.Buffer)
+
+	err := templ.Execute(buf, &TemplateData{Locale: "es"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "02 Jan 2006 a las 15:04"
+	if buf.String() != expected {
+		t.Errorf("Expected %q got %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,100 @@
+package godinez
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDevCertificate(t *testing.T) {
+	dir := t.TempDir()
+
+	cert, err := DevCertificate(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := leaf.VerifyHostname("localhost"); err != nil {
+		t.Errorf("Expected a certificate for localhost: %s", err)
+	}
+
+	t.Run("Cached", func(t *testing.T) {
+		cached, err := DevCertificate(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(cached.Certificate[0], cert.Certificate[0]) {
+			t.Errorf("Expected the cached certificate")
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		defer func() { now = time.Now }()
+		now = func() time.Time { return time.Now().AddDate(2, 0, 0) }
+
+		renewed, err := DevCertificate(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(renewed.Certificate[0], cert.Certificate[0]) {
+			t.Errorf("Expected a new certificate")
+		}
+	})
+
+	info, err := os.Stat(filepath.Join(dir, "key.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected %v got %v", os.FileMode(0600), info.Mode().Perm())
+	}
+}
+
+func TestServeTLS(t *testing.T) {
+	cfg, err := DevTLSConfig(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MinVersion != tls.VersionTLS12 {
+		t.Errorf("Expected TLS 1.2 as the minimum version")
+	}
+	discard := log.New(ioutil.Discard, "", 0)
+	app := NewApp(WithInfoLogger(discard), WithErrorLogger(discard), WithTLSConfig(cfg))
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("secure"))
+	})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go app.serve(ctx, app.NewServer("", h), ln)
+
+	pool := x509.NewCertPool()
+	leaf, _ := x509.ParseCertificate(cfg.Certificates[0].Certificate[0])
+	pool.AddCert(leaf)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+
+	rs, err := client.Get("https://" + ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rs.Body.Close()
+	body, _ := ioutil.ReadAll(rs.Body)
+	if string(body) != "secure" {
+		t.Errorf("Expected %q got %q", "secure", string(body))
+	}
+}
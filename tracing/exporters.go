@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// InMemoryExporter keeps finished spans, mostly for tests.
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+func (e *InMemoryExporter) Export(s *Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, s)
+}
+
+// Spans returns the exported spans in the order they finished.
+func (e *InMemoryExporter) Spans() []*Span {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]*Span{}, e.spans...)
+}
+
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
+
+// StdoutExporter writes each span as a line of JSON, e.g. to os.Stdout.
+type StdoutExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	return &StdoutExporter{w: w}
+}
+
+type jsonSpan struct {
+	Name       string                 `json:"name"`
+	TraceID    string                 `json:"trace_id"`
+	SpanID     string                 `json:"span_id"`
+	ParentID   string                 `json:"parent_id,omitempty"`
+	Start      time.Time              `json:"start"`
+	Duration   string                 `json:"duration"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+func (e *StdoutExporter) Export(s *Span) {
+	s.mu.Lock()
+	js := jsonSpan{
+		Name:       s.Name,
+		TraceID:    s.SpanContext.TraceID.String(),
+		SpanID:     s.SpanContext.SpanID.String(),
+		Start:      s.Start,
+		Duration:   s.Duration().String(),
+		Attributes: s.Attributes,
+		Error:      s.Error,
+	}
+	if s.Parent.IsValid() {
+		js.ParentID = s.Parent.String()
+	}
+	data, err := json.Marshal(js)
+	s.mu.Unlock()
+	if err != nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.w.Write(append(data, '\n'))
+}
@@ -0,0 +1,243 @@
+// Package tracing records spans around requests, template rendering and
+// form validation, propagated with the W3C traceparent header and sent to
+// an Exporter. The span model follows OpenTelemetry, so an exporter can
+// forward spans to a collector.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// SpanContext identifies a span across process boundaries.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent formats sc as a traceparent header value, e.g.
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+// ParseTraceparent parses a traceparent header value. Invalid values,
+// including all-zero ids, are rejected.
+func ParseTraceparent(s string) (SpanContext, bool) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return sc, false
+	}
+	// Version 00 has exactly four fields, later versions may add more.
+	if parts[0] == "00" && len(parts) != 4 {
+		return sc, false
+	}
+	var flags [1]byte
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return sc, false
+	}
+	if _, err := hex.Decode(flags[:], []byte(parts[3])); err != nil {
+		return sc, false
+	}
+	sc.Sampled = flags[0]&1 == 1
+	return sc, sc.IsValid()
+}
+
+// Span is a timed operation within a trace.
+type Span struct {
+	Name        string
+	SpanContext SpanContext
+	Parent      SpanID
+	Start       time.Time
+	End         time.Time
+	Attributes  map[string]interface{}
+	Error       string
+
+	tracer *Tracer
+	mu     sync.Mutex
+	ended  bool
+}
+
+// SetAttribute records key, e.g. "http.status_code", on the span.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Attributes[key] = value
+}
+
+// RecordError marks the span as failed with err. A nil err is ignored.
+func (s *Span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Error = err.Error()
+}
+
+// Finish ends the span and exports it. Only the first call has an effect.
+func (s *Span) Finish() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.End = s.tracer.now()
+	s.mu.Unlock()
+	s.tracer.export(s)
+}
+
+// Duration is the time between Start and End.
+func (s *Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Exporter receives the spans once they finish.
+type Exporter interface {
+	Export(s *Span)
+}
+
+// Tracer starts spans and sends them to its exporter. Spans are still
+// created and propagated without an exporter, they are just not recorded.
+type Tracer struct {
+	mu       sync.RWMutex
+	exporter Exporter
+	now      func() time.Time
+}
+
+// Default is the tracer used by the godinez packages.
+var Default = NewTracer(nil)
+
+func NewTracer(e Exporter) *Tracer {
+	return &Tracer{exporter: e, now: time.Now}
+}
+
+// SetExporter replaces the exporter of t, nil disables exporting.
+func (t *Tracer) SetExporter(e Exporter) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.exporter = e
+}
+
+// SetExporter replaces the exporter of Default.
+func SetExporter(e Exporter) {
+	Default.SetExporter(e)
+}
+
+func (t *Tracer) export(s *Span) {
+	t.mu.RLock()
+	e := t.exporter
+	t.mu.RUnlock()
+	if e != nil && s.SpanContext.Sampled {
+		e.Export(s)
+	}
+}
+
+type contextKey string
+
+var (
+	contextKeySpan   = contextKey("span")
+	contextKeyRemote = contextKey("remote")
+)
+
+// Start starts a span called name, child of the span in ctx or of the
+// remote span set by ContextWithRemote, and returns a context holding it.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+	s := &Span{
+		Name:       name,
+		Start:      t.now(),
+		Attributes: map[string]interface{}{},
+		tracer:     t,
+	}
+	if parent := SpanFromContext(ctx); parent != nil {
+		s.SpanContext.TraceID = parent.SpanContext.TraceID
+		s.SpanContext.Sampled = parent.SpanContext.Sampled
+		s.Parent = parent.SpanContext.SpanID
+	} else if remote, ok := ctx.Value(contextKeyRemote).(SpanContext); ok {
+		s.SpanContext.TraceID = remote.TraceID
+		s.SpanContext.Sampled = remote.Sampled
+		s.Parent = remote.SpanID
+	} else {
+		rand.Read(s.SpanContext.TraceID[:])
+		s.SpanContext.Sampled = true
+	}
+	rand.Read(s.SpanContext.SpanID[:])
+	return context.WithValue(ctx, contextKeySpan, s), s
+}
+
+// Start starts a span with the tracer of the span in ctx, so that it is
+// exported along with its parent, or with Default if there is none.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	if parent := SpanFromContext(ctx); parent != nil && parent.tracer != nil {
+		return parent.tracer.Start(ctx, name)
+	}
+	return Default.Start(ctx, name)
+}
+
+// SpanFromContext returns the current span of ctx or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(contextKeySpan).(*Span)
+	return s
+}
+
+// ContextWithRemote returns a copy of ctx whose spans continue the trace
+// of sc, usually received from another service.
+func ContextWithRemote(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, contextKeyRemote, sc)
+}
+
+// Extract continues the trace of the traceparent header of h, if any.
+func Extract(ctx context.Context, h http.Header) context.Context {
+	if sc, ok := ParseTraceparent(h.Get("traceparent")); ok {
+		return ContextWithRemote(ctx, sc)
+	}
+	return ctx
+}
+
+// Inject sets the traceparent header of h to the current span of ctx, so
+// that outgoing requests continue the trace.
+func Inject(ctx context.Context, h http.Header) {
+	if s := SpanFromContext(ctx); s != nil {
+		h.Set("traceparent", s.SpanContext.Traceparent())
+	}
+}
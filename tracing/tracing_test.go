@@ -0,0 +1,145 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected bool
+		sampled  bool
+	}{
+		{"Sampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true, true},
+		{"Not sampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true, false},
+		{"Future version", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true, true},
+		{"Extra fields in version 00", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false, false},
+		{"Invalid version", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false, false},
+		{"Zero trace id", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", false, false},
+		{"Not hex", "00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01", false, false},
+		{"Empty", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, ok := ParseTraceparent(tt.value)
+			if ok != tt.expected {
+				t.Fatalf("Expected %t got %t", tt.expected, ok)
+			}
+			if ok && sc.Sampled != tt.sampled {
+				t.Errorf("Expected sampled %t got %t", tt.sampled, sc.Sampled)
+			}
+		})
+	}
+
+	value := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, _ := ParseTraceparent(value)
+	if sc.Traceparent() != value {
+		t.Errorf("Expected %q got %q", value, sc.Traceparent())
+	}
+}
+
+func TestStart(t *testing.T) {
+	exporter := &InMemoryExporter{}
+	tracer := NewTracer(exporter)
+	h := http.Header{}
+	h.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx, parent := tracer.Start(Extract(context.Background(), h), "request")
+	_, child := tracer.Start(ctx, "render")
+	child.SetAttribute("template", "home.page.tmpl")
+	child.RecordError(errors.New("boom"))
+	child.Finish()
+	child.Finish()
+	parent.Finish()
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected %d spans got %d", 2, len(spans))
+	}
+	if spans[0].Name != "render" || spans[1].Name != "request" {
+		t.Errorf("Expected spans %q and %q got %q and %q", "render", "request", spans[0].Name, spans[1].Name)
+	}
+	if parent.SpanContext.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected the remote trace id got %q", parent.SpanContext.TraceID)
+	}
+	if parent.Parent.String() != "00f067aa0ba902b7" {
+		t.Errorf("Expected the remote parent got %q", parent.Parent)
+	}
+	if child.SpanContext.TraceID != parent.SpanContext.TraceID || child.Parent != parent.SpanContext.SpanID {
+		t.Errorf("Expected render to be a child of request")
+	}
+	if child.Error != "boom" {
+		t.Errorf("Expected %q got %q", "boom", child.Error)
+	}
+
+	out := http.Header{}
+	Inject(ctx, out)
+	if out.Get("traceparent") != parent.SpanContext.Traceparent() {
+		t.Errorf("Expected %q got %q", parent.SpanContext.Traceparent(), out.Get("traceparent"))
+	}
+}
+
+func TestStartParentTracer(t *testing.T) {
+	exporter := &InMemoryExporter{}
+	tracer := NewTracer(exporter)
+
+	ctx, parent := tracer.Start(context.Background(), "request")
+	_, child := Start(ctx, "render")
+	child.Finish()
+	parent.Finish()
+	_, orphan := Start(context.Background(), "orphan")
+	orphan.Finish()
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected %d spans got %d", 2, len(spans))
+	}
+	if spans[0].Name != "render" {
+		t.Errorf("Expected %q got %q", "render", spans[0].Name)
+	}
+}
+
+func TestNotSampled(t *testing.T) {
+	exporter := &InMemoryExporter{}
+	tracer := NewTracer(exporter)
+	h := http.Header{}
+	h.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+
+	_, s := tracer.Start(Extract(context.Background(), h), "request")
+	s.Finish()
+
+	if len(exporter.Spans()) != 0 {
+		t.Errorf("Expected no exported spans got %d", len(exporter.Spans()))
+	}
+}
+
+func TestStdoutExporter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tracer := NewTracer(NewStdoutExporter(buf))
+	start := time.Date(2019, time.January, 2, 15, 4, 0, 0, time.UTC)
+	tracer.now = func() time.Time { return start }
+
+	_, s := tracer.Start(context.Background(), "request")
+	s.SetAttribute("http.method", "GET")
+	tracer.now = func() time.Time { return start.Add(time.Millisecond) }
+	s.Finish()
+
+	var actual map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &actual); err != nil {
+		t.Fatal(err)
+	}
+	if actual["name"] != "request" || actual["duration"] != "1ms" || actual["trace_id"] != s.SpanContext.TraceID.String() {
+		t.Errorf("Unexpected span %s", buf.String())
+	}
+	if _, ok := actual["parent_id"]; ok {
+		t.Errorf("Expected no parent_id for a root span")
+	}
+}